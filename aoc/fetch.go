@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	aocClient "github.com/scottkerkvliet/advent-of-code-2023/utils/aoc-client"
+)
+
+func fetch(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	day := flags.Int("day", 0, "the day to fetch the input for")
+	root := flags.String("root", ".", "the repository root containing the dayNN directories")
+	force := flags.Bool("force", false, "whether to download the input even if it is already cached")
+	baseURL := flags.String("base-url", aocClient.DefaultBaseURL, "the Advent of Code server to use")
+	flags.Parse(args)
+	if err := checkDay(*day); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dayDir(*root, *day), "input.txt")
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 && !*force {
+		fmt.Printf("Using cached input %v (use -force to download it again)\n", path)
+		return nil
+	}
+
+	client, err := newClient(*baseURL)
+	if err != nil {
+		return err
+	}
+	input, err := client.FetchInput(*day)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, input, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Saved day %v input to %v (%v bytes)\n", *day, path, len(input))
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"time"
+
+	aocClient "github.com/scottkerkvliet/advent-of-code-2023/utils/aoc-client"
+)
+
+// A record of every answer submitted, so wrong answers are never sent twice.
+type Ledger struct {
+	path     string
+	Attempts []Attempt `json:"attempts"`
+}
+
+type Attempt struct {
+	Day     int               `json:"day"`
+	Part    int               `json:"part"`
+	Answer  string            `json:"answer"`
+	Verdict aocClient.Verdict `json:"verdict"`
+	Time    time.Time         `json:"time"`
+}
+
+// What the ledger knows about the answer to a part. The bounds are exclusive.
+type KnownAnswers struct {
+	correct   string
+	wrong     map[string]aocClient.Verdict
+	low, high *int
+}
+
+func (k KnownAnswers) String() string {
+	low, high := "?", "?"
+	if k.low != nil {
+		low = strconv.Itoa(*k.low)
+	}
+	if k.high != nil {
+		high = strconv.Itoa(*k.high)
+	}
+	return fmt.Sprintf("%v wrong answer(s), answer is between %v and %v", len(k.wrong), low, high)
+}
+
+func LoadLedger(path string) (*Ledger, error) {
+	ledger := &Ledger{path: path}
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, ledger); err != nil {
+		return nil, fmt.Errorf("Error parsing ledger %q: %w", path, err)
+	}
+	return ledger, nil
+}
+
+func (l *Ledger) Save() error {
+	contents, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, append(contents, '\n'), 0644)
+}
+
+func (l *Ledger) Record(attempt Attempt) {
+	l.Attempts = append(l.Attempts, attempt)
+}
+
+func (l *Ledger) Known(day, part int) KnownAnswers {
+	known := KnownAnswers{wrong: make(map[string]aocClient.Verdict)}
+	for _, attempt := range l.Attempts {
+		if attempt.Day != day || attempt.Part != part {
+			continue
+		}
+		switch attempt.Verdict {
+		case aocClient.Correct:
+			known.correct = attempt.Answer
+		case aocClient.TooLow, aocClient.TooHigh, aocClient.Wrong:
+			known.wrong[attempt.Answer] = attempt.Verdict
+		}
+
+		value, err := strconv.Atoi(attempt.Answer)
+		if err != nil {
+			continue
+		}
+		if attempt.Verdict == aocClient.TooLow && (known.low == nil || value > *known.low) {
+			known.low = &value
+		}
+		if attempt.Verdict == aocClient.TooHigh && (known.high == nil || value < *known.high) {
+			known.high = &value
+		}
+	}
+	return known
+}
+
+// Returns an error if the answer is already known to be wrong, or the part is already solved.
+func (l *Ledger) Check(day, part int, answer string) error {
+	known := l.Known(day, part)
+	if known.correct != "" {
+		return fmt.Errorf("Day %v part %v is already solved with answer %v", day, part, known.correct)
+	}
+	if verdict, ok := known.wrong[answer]; ok {
+		return fmt.Errorf("Answer %v was already submitted and was %v (%v)", answer, verdict, known)
+	}
+	if value, err := strconv.Atoi(answer); err == nil {
+		if (known.low != nil && value <= *known.low) || (known.high != nil && value >= *known.high) {
+			return fmt.Errorf("Answer %v is outside the known bounds (%v)", answer, known)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	aocClient "github.com/scottkerkvliet/advent-of-code-2023/utils/aoc-client"
+)
+
+// A ledger for day 1 part 1 with the given answers and verdicts, in order.
+func testLedger(attempts ...Attempt) *Ledger {
+	ledger := &Ledger{}
+	for _, attempt := range attempts {
+		if attempt.Day == 0 {
+			attempt.Day, attempt.Part = 1, 1
+		}
+		ledger.Record(attempt)
+	}
+	return ledger
+}
+
+func TestCheckBounds(t *testing.T) {
+	ledger := testLedger(
+		Attempt{Answer: "100", Verdict: aocClient.TooLow},
+		Attempt{Answer: "150", Verdict: aocClient.TooLow},
+		Attempt{Answer: "300", Verdict: aocClient.TooHigh},
+		Attempt{Answer: "200", Verdict: aocClient.TooHigh},
+	)
+	tests := []struct {
+		answer string
+		ok     bool
+	}{
+		{"99", false},
+		{"100", false},
+		{"120", false},
+		// The bounds are exclusive, so the bound itself is refused
+		{"150", false},
+		{"151", true},
+		{"175", true},
+		{"199", true},
+		{"200", false},
+		{"250", false},
+		{"301", false},
+		{"-5", false},
+	}
+	for _, test := range tests {
+		if err := ledger.Check(1, 1, test.answer); (err == nil) != test.ok {
+			t.Errorf("Answer %v: got error %v, expected ok to be %v", test.answer, err, test.ok)
+		}
+	}
+
+	known := ledger.Known(1, 1)
+	if *known.low != 150 || *known.high != 200 {
+		t.Errorf("Got bounds %v and %v, expected 150 and 200", *known.low, *known.high)
+	}
+	if want := "4 wrong answer(s), answer is between 150 and 200"; known.String() != want {
+		t.Errorf("Got %q, expected %q", known, want)
+	}
+}
+
+func TestCheckWrongAnswers(t *testing.T) {
+	ledger := testLedger(
+		Attempt{Answer: "42", Verdict: aocClient.Wrong},
+		Attempt{Answer: "abc", Verdict: aocClient.Wrong},
+		Attempt{Answer: "xyz", Verdict: aocClient.TooLow},
+	)
+	tests := []struct {
+		answer string
+		ok     bool
+	}{
+		{"42", false},
+		{"41", true},
+		{"43", true},
+		{"abc", false},
+		{"xyz", false},
+		// Non-numeric answers don't set or get checked against bounds
+		{"abd", true},
+	}
+	for _, test := range tests {
+		if err := ledger.Check(1, 1, test.answer); (err == nil) != test.ok {
+			t.Errorf("Answer %q: got error %v, expected ok to be %v", test.answer, err, test.ok)
+		}
+	}
+	if known := ledger.Known(1, 1); known.low != nil || known.high != nil {
+		t.Errorf("Got bounds from non-numeric or plain wrong answers: %v", known)
+	}
+}
+
+func TestCheckSolved(t *testing.T) {
+	ledger := testLedger(
+		Attempt{Answer: "10", Verdict: aocClient.TooLow},
+		Attempt{Answer: "12", Verdict: aocClient.Correct},
+	)
+	for _, answer := range []string{"12", "11", "13", "abc"} {
+		if err := ledger.Check(1, 1, answer); err == nil {
+			t.Errorf("Answer %v was allowed for a solved part", answer)
+		}
+	}
+
+	// Other parts and days are unaffected
+	if err := ledger.Check(1, 2, "12"); err != nil {
+		t.Errorf("Part 2 was refused: %v", err)
+	}
+	if err := ledger.Check(2, 1, "5"); err != nil {
+		t.Errorf("Day 2 was refused: %v", err)
+	}
+}
+
+func TestCheckIgnoresInconclusiveVerdicts(t *testing.T) {
+	ledger := testLedger(
+		Attempt{Answer: "7", Verdict: aocClient.RateLimited},
+		Attempt{Answer: "8", Verdict: aocClient.Unknown},
+		Attempt{Answer: "9", Verdict: aocClient.AlreadySolved},
+	)
+	for _, answer := range []string{"7", "8", "9"} {
+		if err := ledger.Check(1, 1, answer); err != nil {
+			t.Errorf("Answer %v was refused: %v", answer, err)
+		}
+	}
+}
+
+func TestLedgerSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	ledger, err := LoadLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger.Record(Attempt{Day: 3, Part: 2, Answer: "500", Verdict: aocClient.TooHigh})
+	if err := ledger.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Check(3, 2, "500"); err == nil {
+		t.Error("A saved wrong answer was allowed after loading the ledger")
+	}
+	if err := loaded.Check(3, 2, "499"); err != nil {
+		t.Errorf("Answer 499 was refused: %v", err)
+	}
+}
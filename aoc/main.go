@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	aocClient "github.com/scottkerkvliet/advent-of-code-2023/utils/aoc-client"
+)
+
+const (
+	year       = 2023
+	sessionEnv = "AOC_SESSION"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"fetch":  {"download a day's puzzle input into dayNN/input.txt", fetch},
+	"new":    {"create a new dayNN directory from the templates", newDay},
+	"run":    {"run one or every registered day", run},
+	"submit": {"submit an answer and record it in the ledger", submit},
+}
+
+// Every aoc command is a new process, so the time of the last request is kept in the user's
+// cache directory to throttle requests across commands.
+func newClient(baseURL string) (*aocClient.Client, error) {
+	session := os.Getenv(sessionEnv)
+	if session == "" {
+		return nil, fmt.Errorf("Set $%v to your adventofcode.com session cookie", sessionEnv)
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	client := aocClient.NewClient(&http.Client{Timeout: 30 * time.Second}, baseURL, session, year)
+	client.SetLastRequestFile(filepath.Join(cacheDir, "aoc", "last-request"))
+	return client, nil
+}
+
+func dayDir(root string, day int) string {
+	return fmt.Sprintf("%v/day%02d", root, day)
+}
+
+func checkDay(day int) error {
+	if day < 1 || day > 25 {
+		return fmt.Errorf("Expected a day from 1 to 25, got %v", day)
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc <command> [flags]\n\nCommands:")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8v %v\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nThe session token is read from $%v.\n", sessionEnv)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "aoc %v: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.tmpl"))
+
+// Executes the template into a new file, failing if the file already exists.
+func createFromTemplate(path, name string, data any) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return templates.ExecuteTemplate(file, name, data)
+}
+
+// Adds the day to the registered days by regenerating days.go.
+func registerDay(root string, day int) error {
+	registered := slices.Clone(days)
+	if !slices.Contains(registered, day) {
+		registered = append(registered, day)
+	}
+	slices.Sort(registered)
+
+	path := filepath.Join(root, "aoc", "days.go")
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return templates.ExecuteTemplate(file, "days.go.tmpl", struct{ Days []int }{registered})
+}
+
+func newDay(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	day := flags.Int("day", 0, "the day to create")
+	root := flags.String("root", ".", "the repository root containing the dayNN directories")
+	flags.Parse(args)
+	if err := checkDay(*day); err != nil {
+		return err
+	}
+
+	dir := dayDir(*root, *day)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%v already exists", dir)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+
+	data := struct{ Day int }{*day}
+	for _, name := range []string{"main.go", "main_test.go"} {
+		if err := createFromTemplate(filepath.Join(dir, name), name+".tmpl", data); err != nil {
+			return err
+		}
+	}
+	for _, name := range []string{"example.txt", "input.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			return err
+		}
+	}
+	if err := registerDay(*root, *day); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %v, fetch its input with \"aoc fetch -day %v\"\n", dir, *day)
+	return nil
+}
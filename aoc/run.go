@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
+)
+
+// Runs the days with "go run", passing any arguments after the flags on to them.
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	day := flags.Int("day", 0, "the day to run")
+	all := flags.Bool("all", false, "whether to run every registered day")
+	root := flags.String("root", ".", "the repository root containing the dayNN directories")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc run (-day N | -all) [flags] [-- day flags]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	toRun := days
+	if !*all {
+		if !slices.Contains(days, *day) {
+			return fmt.Errorf("Day %v is not registered, registered days are %v", *day, days)
+		}
+		toRun = []int{*day}
+	}
+
+	for _, d := range toRun {
+		if *all {
+			fmt.Printf("=== Day %v\n", d)
+		}
+		cmd := exec.Command("go", append([]string{"run", "."}, flags.Args()...)...)
+		cmd.Dir = dayDir(*root, d)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("Day %v: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// Runs the day's part on its input with -format json and returns the answer it printed.
+func dayAnswer(root string, day, part int) (string, error) {
+	cmd := exec.Command("go", "run", ".", fmt.Sprintf("-%v", part), "-format", runner.JSONFormat)
+	cmd.Dir = dayDir(root, day)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Day %v part %v: %w", day, part, err)
+	}
+
+	// Keep numbers as written, so large answers don't lose precision
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	var result runner.Result
+	if err := decoder.Decode(&result); err != nil {
+		return "", fmt.Errorf("Error reading the answer of day %v part %v: %w", day, part, err)
+	}
+	answer := strings.TrimSpace(fmt.Sprint(result.Answer))
+	if answer == "" {
+		return "", fmt.Errorf("Day %v part %v gave an empty answer", day, part)
+	}
+	return answer, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	aocClient "github.com/scottkerkvliet/advent-of-code-2023/utils/aoc-client"
+)
+
+func submit(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	day := flags.Int("day", 0, "the day to submit an answer for")
+	part := flags.Int("part", 0, "the part to submit an answer for, 1 or 2")
+	answer := flags.String("answer", "", "the answer to submit, found by running the day's part on its input if not given")
+	root := flags.String("root", ".", "the repository root containing the dayNN directories")
+	ledgerPath := flags.String("ledger", "aoc-ledger.json", "the file recording every submitted answer")
+	baseURL := flags.String("base-url", aocClient.DefaultBaseURL, "the Advent of Code server to use")
+	flags.Parse(args)
+	if err := checkDay(*day); err != nil {
+		return err
+	}
+	if *part != 1 && *part != 2 {
+		return fmt.Errorf("Expected part 1 or 2, got %v", *part)
+	}
+	if *answer = strings.TrimSpace(*answer); *answer == "" {
+		found, err := dayAnswer(*root, *day, *part)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Day %v part %v answered %v\n", *day, *part, found)
+		*answer = found
+	}
+
+	ledger, err := LoadLedger(*ledgerPath)
+	if err != nil {
+		return err
+	}
+	if err := ledger.Check(*day, *part, *answer); err != nil {
+		return err
+	}
+
+	client, err := newClient(*baseURL)
+	if err != nil {
+		return err
+	}
+	result, err := client.Submit(*day, *part, *answer)
+	if err != nil {
+		return err
+	}
+
+	ledger.Record(Attempt{Day: *day, Part: *part, Answer: *answer, Verdict: result.Verdict, Time: time.Now()})
+	if err := ledger.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Day %v part %v answer %v: %v\n", *day, *part, *answer, result.Verdict)
+	if result.Verdict != aocClient.Correct {
+		fmt.Println(result.Message)
+		fmt.Println(ledger.Known(*day, *part))
+	}
+	return nil
+}
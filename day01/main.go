@@ -3,49 +3,63 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
-	"strconv"
 	"strings"
-	"unicode"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
+/********** Line Reading **********/
+
+// Returns the calibration value of a line, or false if the line has no digit.
+type lineParser func(line string) (int, bool)
+
+// In strict mode every line must hold a digit, otherwise blank lines are skipped.
+func getLineReader(strict bool, parse lineParser) fileReader.NumberedLineReader[int] {
+	return func(lineNumber int, line string) (int, error) {
+		if len(strings.TrimSpace(line)) == 0 {
+			if !strict {
+				return 0, fileReader.ErrSkipLine
+			}
+			return 0, fmt.Errorf("line %v is blank (use -lenient to skip blank lines)", lineNumber)
+		}
+		value, ok := parse(line)
+		if !ok {
+			return 0, fmt.Errorf("no digit found on line %v: %q", lineNumber, line)
+		}
+		return value, nil
+	}
+}
+
 /********** Part 1 **********/
-func getNumberFromLine(line string) (int, error) {
-	var first, last rune
+func getNumberFromLine(line string) (int, bool) {
+	first, last := -1, -1
 	for _, char := range line {
-		if unicode.IsDigit(char) {
-			if first == 0 {
-				first = char
+		if char >= '0' && char <= '9' {
+			if first == -1 {
+				first = int(char - '0')
 			}
-			last = char
+			last = int(char - '0')
 		}
 	}
 
-	value, err := strconv.Atoi(string(first) + string(last))
-	if err != nil {
-		return 0, err
+	if first == -1 {
+		return 0, false
 	}
-	return value, nil
+	return first*10 + last, true
 }
 
-func part1(file string) error {
-	values, err := fileReader.ReadFileByLine(file, getNumberFromLine)
+func part1(file string, strict bool, workers int) (int, error) {
+	sum, err := fileReader.ReduceFileByLineParallel(file, workers, getLineReader(strict, getNumberFromLine), 0, fileReader.Sum[int])
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	sum := 0
-	for _, value := range values {
-		sum += value
-	}
-	fmt.Printf("The sum of all values for part 1 was %v\n", sum)
-	return nil
+	return sum, nil
 }
 
 /********** Part 2 **********/
-func getTextNumbersFromLine(line string) (int, error) {
+func getTextNumbersFromLine(line string) (int, bool) {
 	firstIndex, lastIndex := len(line), -1
 	var first, last rune
 	// Handle digits
@@ -75,49 +89,31 @@ func getTextNumbersFromLine(line string) (int, error) {
 		}
 	}
 
-	value, err := strconv.Atoi(string(first) + string(last))
-	if err != nil {
-		// fmt.Printf("====> Error converting. firstIndex: %v, first: %v, lastIndex: %v, last: %v\n", firstIndex, first, lastIndex, last)
-		return 0, fmt.Errorf("error parsing line %q: %w", line, err)
+	if lastIndex == -1 {
+		return 0, false
 	}
-	return value, nil
+	return int(first-'0')*10 + int(last-'0'), true
 }
 
-func part2(file string) error {
-	values, err := fileReader.ReadFileByLine(file, getTextNumbersFromLine)
+func part2(file string, strict bool, workers int) (int, error) {
+	sum, err := fileReader.ReduceFileByLineParallel(file, workers, getLineReader(strict, getTextNumbersFromLine), 0, fileReader.Sum[int])
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	sum := 0
-	for _, value := range values {
-		sum += value
-	}
-	fmt.Printf("The sum of all values for part 2 was %v\n", sum)
-	return nil
+	return sum, nil
 }
 
 /********** main **********/
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "if provided, execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "if provided, execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
+	workersFlag := flag.Int("workers", 0, "the number of lines to solve at once, defaults to one per CPU")
+	lenientFlag := flag.Bool("lenient", false, "if provided, skip blank lines instead of failing on them")
 
-	return
+	runner.Day{
+		Number: 1,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, !*lenientFlag, *workersFlag) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, !*lenientFlag, *workersFlag) }},
+		},
+	}.Main()
 }
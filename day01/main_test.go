@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part1("input.txt", true, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part2("input.txt", true, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
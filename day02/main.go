@@ -3,15 +3,46 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
-	"strconv"
+	"os"
+	"slices"
 	"strings"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
-type Draw struct {
-	green, blue, red int
+/********** Colors **********/
+
+// The colors of cube that may appear in a draw, in the order they are reported.
+var knownColors = []string{"red", "green", "blue"}
+
+func RegisterColor(color string) {
+	if !slices.Contains(knownColors, color) {
+		knownColors = append(knownColors, color)
+	}
+}
+
+/********** Types **********/
+
+// Draw maps a cube color to the number of cubes of that color. Missing colors count as zero.
+type Draw map[string]int
+
+func (d Draw) String() string {
+	var parts []string
+	for _, color := range knownColors {
+		parts = append(parts, fmt.Sprintf("%v %v", d[color], color))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// The product of the number of cubes for every known color.
+func (d Draw) Power() int {
+	power := 1
+	for _, color := range knownColors {
+		power *= d[color]
+	}
+	return power
 }
 
 type Game struct {
@@ -22,139 +53,179 @@ type Game struct {
 func (g *Game) MaxDraw() Draw {
 	maxDraw := Draw{}
 	for _, draw := range g.draws {
-		maxDraw.green = max(maxDraw.green, draw.green)
-		maxDraw.red = max(maxDraw.red, draw.red)
-		maxDraw.blue = max(maxDraw.blue, draw.blue)
+		for color, count := range draw {
+			maxDraw[color] = max(maxDraw[color], count)
+		}
 	}
 	return maxDraw
 }
 
-func getIntFromString(s string) (int, error) {
-	return strconv.Atoi(strings.TrimSpace(s))
+// Whether every draw in the game could have come from the given bag.
+func (g *Game) IsPossibleWith(bag Draw) bool {
+	for color, count := range g.MaxDraw() {
+		if count > bag[color] {
+			return false
+		}
+	}
+	return true
 }
 
-func getDrawFromString(s string) (Draw, error) {
-	greenSuffix := " green"
-	redSuffix := " red"
-	blueSuffix := " blue"
-
-	draw := Draw{}
-	for _, cubePart := range strings.Split(s, ",") {
-		switch {
-		case strings.HasSuffix(cubePart, greenSuffix):
-			numCubes, err := getIntFromString(strings.TrimSuffix(cubePart, greenSuffix))
-			if err != nil {
-				return draw, err
-			}
-			draw.green = numCubes
-		case strings.HasSuffix(cubePart, redSuffix):
-			numCubes, err := getIntFromString(strings.TrimSuffix(cubePart, redSuffix))
-			if err != nil {
-				return draw, err
-			}
-			draw.red = numCubes
-		case strings.HasSuffix(cubePart, blueSuffix):
-			numCubes, err := getIntFromString(strings.TrimSuffix(cubePart, blueSuffix))
-			if err != nil {
-				return draw, err
-			}
-			draw.blue = numCubes
-		default:
-			return draw, fmt.Errorf("Draw string had no expected suffix %q", s)
+// The smallest bag that could have produced every one of the games.
+func MinimumBag(games []*Game) Draw {
+	bag := Draw{}
+	for _, game := range games {
+		for color, count := range game.MaxDraw() {
+			bag[color] = max(bag[color], count)
 		}
 	}
-	return draw, nil
+	return bag
 }
 
-func getGameFromLine(line string) (*Game, error) {
-	gamePrefix := "Game "
+/********** File Functions **********/
 
-	lineParts := strings.Split(line, ":")
-	if len(lineParts) != 2 {
-		return nil, fmt.Errorf("Got %v colons in line, wanted 1", len(lineParts)-1)
-	}
-	idPart := lineParts[0]
-	drawPart := lineParts[1]
+// A count of cubes of one color within a draw, like "3 blue".
+type cubeCount struct {
+	count int
+	color string
+}
 
-	if !strings.HasPrefix(idPart, gamePrefix) {
-		return nil, fmt.Errorf("Line does not begin with %q", gamePrefix)
+func knownColor(color string) (string, error) {
+	if !slices.Contains(knownColors, color) {
+		return "", fmt.Errorf("unknown color %q", color)
 	}
-	id, err := getIntFromString(strings.TrimPrefix(idPart, gamePrefix))
-	if err != nil {
-		return nil, err
-	}
-	game := &Game{id, []Draw{}}
+	return color, nil
+}
+
+// Registers the color, so that games may draw it.
+func newColor(color string) (string, error) {
+	RegisterColor(color)
+	return color, nil
+}
 
-	drawParts := strings.Split(drawPart, ";")
-	if len(drawParts) == 0 {
-		return nil, fmt.Errorf("Line has no draws %q", line)
+func cubeCountParser(color func(string) (string, error)) parser.Parser[cubeCount] {
+	var c cubeCount
+	return parser.Map(parser.Sequence(
+		parser.Into(parser.Int(), &c.count),
+		parser.RequiredSpaces(),
+		parser.Into(parser.Map(parser.Word(), color), &c.color),
+	), func(struct{}) (cubeCount, error) { return c, nil })
+}
+
+// draw = cubes { "," cubes }, where color checks or registers each color
+func drawParser(color func(string) (string, error)) parser.Parser[Draw] {
+	return parser.Map(parser.SepBy(parser.Token(cubeCountParser(color)), parser.Literal(",")), func(cubes []cubeCount) (Draw, error) {
+		draw := Draw{}
+		for _, c := range cubes {
+			draw[c.color] = c.count
+		}
+		return draw, nil
+	})
+}
+
+// game = "Game" id ":" draw { ";" draw }
+func getGameFromLine(line string) (*Game, error) {
+	game := &Game{}
+	grammar := parser.Sequence(
+		parser.Literal("Game"),
+		parser.RequiredSpaces(),
+		parser.Into(parser.Int(), &game.id),
+		parser.Literal(":"),
+		parser.Into(parser.SepBy(drawParser(knownColor), parser.Literal(";")), &game.draws),
+	)
+	if _, err := parser.Parse(grammar, line); err != nil {
+		return nil, fmt.Errorf("Error parsing game %q: %w", line, err)
 	}
-	for _, drawString := range drawParts {
-		draw, err := getDrawFromString(drawString)
+	return game, nil
+}
+
+// Reads the bag from the config file if one is given, otherwise from the flag value.
+// Any new colors in the bag are registered.
+func getBag(bagString, bagFile string) (Draw, error) {
+	if bagFile != "" {
+		contents, err := os.ReadFile(bagFile)
 		if err != nil {
-			return nil, err
+			return Draw{}, err
 		}
-		game.draws = append(game.draws, draw)
+		bagString = string(contents)
 	}
-
-	return game, nil
+	bag, err := parser.Parse(drawParser(newColor), strings.TrimSpace(bagString))
+	if err != nil {
+		return Draw{}, fmt.Errorf("Error parsing bag: %w", err)
+	}
+	return bag, nil
 }
 
-func part1(file string) error {
-	games, err := fileReader.ReadFileByLine(file, getGameFromLine)
+func part1(file string, bag Draw, workers int) (int, error) {
+	gameIdSum, err := fileReader.ReduceFileByLineParallel(file, workers, func(_ int, line string) (int, error) {
+		game, err := getGameFromLine(line)
+		if err != nil || !game.IsPossibleWith(bag) {
+			return 0, err
+		}
+		return game.id, nil
+	}, 0, fileReader.Sum[int])
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var gameIdSum int
-	for _, game := range games {
-		minCubes := game.MaxDraw()
-		if minCubes.red <= 12 && minCubes.green <= 13 && minCubes.blue <= 14 {
-			gameIdSum += game.id
+	return gameIdSum, nil
+}
+
+func part2(file string, workers int) (int, error) {
+	powerSum, err := fileReader.ReduceFileByLineParallel(file, workers, func(_ int, line string) (int, error) {
+		game, err := getGameFromLine(line)
+		if err != nil {
+			return 0, err
 		}
+		return game.MaxDraw().Power(), nil
+	}, 0, fileReader.Sum[int])
+	if err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("The sum of all valid games in part 1 is %v\n", gameIdSum)
-	return nil
+	return powerSum, nil
 }
 
-func part2(file string) error {
+func minBag(file string) error {
 	games, err := fileReader.ReadFileByLine(file, getGameFromLine)
 	if err != nil {
 		return err
 	}
 
-	var powerSum int
-	for _, game := range games {
-		minCubes := game.MaxDraw()
-		power := minCubes.blue * minCubes.green * minCubes.red
-		powerSum += power
-	}
+	bag := MinimumBag(games)
 
-	fmt.Printf("The sum of all powers in part 2 is %v\n", powerSum)
+	fmt.Printf("The smallest bag for all games has %v cubes\n", bag)
 	return nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	return
+	workersFlag := flag.Int("workers", 0, "the number of lines to solve at once, defaults to one per CPU")
+	bagFlag := flag.String("bag", "12 red, 13 green, 14 blue", "the bag of cubes to check games against in puzzle 1")
+	bagFileFlag := flag.String("bag-file", "", "a file containing the bag of cubes, overrides -bag")
+	reportFormatFlag := flag.String("report-format", "table", "the format of the report, either \"table\" or \"csv\"")
+	topFlag := flag.Int("top", 5, "the number of games to list by power in the report")
+	colorsFlag := flag.String("colors", "", "a comma separated list of cube colors to allow besides red, green, blue and those in the bag")
+
+	var bag Draw
+	runner.Day{
+		Number: 2,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, bag, *workersFlag) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, *workersFlag) }},
+		},
+		Tools: []runner.Tool{
+			{Name: "min-bag", Usage: "whether to find the smallest bag that satisfies every game", Run: minBag},
+			{Name: "report", Usage: "whether to print statistics about the games", Run: func(file string) error {
+				return report(os.Stdout, file, *reportFormatFlag, *topFlag)
+			}},
+		},
+		Setup: func() (err error) {
+			for _, color := range strings.Split(*colorsFlag, ",") {
+				if color = strings.TrimSpace(color); color != "" {
+					RegisterColor(color)
+				}
+			}
+			bag, err = getBag(*bagFlag, *bagFileFlag)
+			return err
+		},
+	}.Main()
 }
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func BenchmarkPart1(b *testing.B) {
+	bag, err := getBag("12 red, 13 green, 14 blue", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := part1("input.txt", bag, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part2("input.txt", 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+)
+
+/********** Statistics **********/
+
+type ColorStats struct {
+	min, max, total int
+}
+
+// Draw statistics for a set of draws. A draw without a color counts as zero cubes of it.
+type DrawStats struct {
+	draws  int
+	colors map[string]*ColorStats
+}
+
+func (ds *DrawStats) Add(draw Draw) {
+	if ds.colors == nil {
+		ds.colors = make(map[string]*ColorStats)
+	}
+	for _, color := range knownColors {
+		count := draw[color]
+		stats, ok := ds.colors[color]
+		if !ok {
+			ds.colors[color] = &ColorStats{min: count, max: count, total: count}
+			continue
+		}
+		stats.min = min(stats.min, count)
+		stats.max = max(stats.max, count)
+		stats.total += count
+	}
+	ds.draws++
+}
+
+func (ds *DrawStats) Mean(color string) float64 {
+	if ds.draws == 0 {
+		return 0
+	}
+	return float64(ds.colors[color].total) / float64(ds.draws)
+}
+
+func (g *Game) Stats() *DrawStats {
+	stats := &DrawStats{}
+	for _, draw := range g.draws {
+		stats.Add(draw)
+	}
+	return stats
+}
+
+// The power at the given quantile (0 to 1) of an ascending list of powers.
+func quantile(sortedPowers []int, q float64) int {
+	if len(sortedPowers) == 0 {
+		return 0
+	}
+	return sortedPowers[int(q*float64(len(sortedPowers)-1))]
+}
+
+/********** Report **********/
+
+type reportTable struct {
+	title  string
+	header []string
+	rows   [][]string
+}
+
+func statsHeader(first string) []string {
+	header := []string{first, "draws"}
+	for _, color := range knownColors {
+		header = append(header, color+" min", color+" max", color+" mean")
+	}
+	return header
+}
+
+func statsRow(first string, stats *DrawStats) []string {
+	row := []string{first, strconv.Itoa(stats.draws)}
+	for _, color := range knownColors {
+		if stats.draws == 0 {
+			row = append(row, "0", "0", "0.00")
+			continue
+		}
+		colorStats := stats.colors[color]
+		row = append(row, strconv.Itoa(colorStats.min), strconv.Itoa(colorStats.max), fmt.Sprintf("%.2f", stats.Mean(color)))
+	}
+	return row
+}
+
+func buildReport(games []*Game, topN int) []*reportTable {
+	gameTable := &reportTable{title: "Games", header: append(statsHeader("game"), "power")}
+	overall := &DrawStats{}
+	var powers []int
+	for _, game := range games {
+		power := game.MaxDraw().Power()
+		powers = append(powers, power)
+		gameTable.rows = append(gameTable.rows, append(statsRow(strconv.Itoa(game.id), game.Stats()), strconv.Itoa(power)))
+		for _, draw := range game.draws {
+			overall.Add(draw)
+		}
+	}
+
+	overallTable := &reportTable{title: "Overall", header: statsHeader("games")}
+	overallTable.rows = append(overallTable.rows, statsRow(strconv.Itoa(len(games)), overall))
+
+	slices.Sort(powers)
+	var powerSum int
+	for _, power := range powers {
+		powerSum += power
+	}
+	var meanPower float64
+	if len(powers) > 0 {
+		meanPower = float64(powerSum) / float64(len(powers))
+	}
+	powerTable := &reportTable{title: "Power distribution", header: []string{"min", "p25", "median", "p75", "max", "mean", "sum"}}
+	powerTable.rows = append(powerTable.rows, []string{
+		strconv.Itoa(quantile(powers, 0)),
+		strconv.Itoa(quantile(powers, 0.25)),
+		strconv.Itoa(quantile(powers, 0.5)),
+		strconv.Itoa(quantile(powers, 0.75)),
+		strconv.Itoa(quantile(powers, 1)),
+		fmt.Sprintf("%.2f", meanPower),
+		strconv.Itoa(powerSum),
+	})
+
+	byPower := slices.Clone(games)
+	slices.SortStableFunc(byPower, func(a, b *Game) int {
+		return b.MaxDraw().Power() - a.MaxDraw().Power()
+	})
+	topTable := &reportTable{title: fmt.Sprintf("Top %v games by power", topN), header: []string{"rank", "game", "power", "minimum cubes"}}
+	for i, game := range byPower[:min(topN, len(byPower))] {
+		maxDraw := game.MaxDraw()
+		topTable.rows = append(topTable.rows, []string{strconv.Itoa(i + 1), strconv.Itoa(game.id), strconv.Itoa(maxDraw.Power()), maxDraw.String()})
+	}
+
+	return []*reportTable{gameTable, overallTable, powerTable, topTable}
+}
+
+func writeTables(w io.Writer, tables []*reportTable) error {
+	for i, table := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%v\n", table.title)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		for _, row := range append([][]string{table.header}, table.rows...) {
+			for _, cell := range row {
+				fmt.Fprintf(tw, "%v\t", cell)
+			}
+			fmt.Fprintln(tw)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Each table is written as its own CSV block with a header, separated by blank lines.
+func writeCSV(w io.Writer, tables []*reportTable) error {
+	for i, table := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(table.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(table.rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func report(w io.Writer, file, format string, topN int) error {
+	games, err := fileReader.ReadFileByLine(file, getGameFromLine)
+	if err != nil {
+		return err
+	}
+
+	tables := buildReport(games, topN)
+	switch format {
+	case "table":
+		return writeTables(w, tables)
+	case "csv":
+		return writeCSV(w, tables)
+	default:
+		return fmt.Errorf("Unknown report format %q, expected \"table\" or \"csv\"", format)
+	}
+}
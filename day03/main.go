@@ -3,174 +3,261 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
 	"slices"
 	"strconv"
-	"unicode"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
-type Number struct {
-	value int
+/********** Types **********/
+
+// A number in the schematic, spanning columns [start, end) of its row.
+type PartNumber struct {
+	value, row, start, end int
+}
+
+type Symbol struct {
+	char     rune
+	row, col int
+}
+
+type Schematic struct {
+	lines   [][]rune
+	numbers []PartNumber
+	symbols []Symbol
+	// The indices of the numbers adjacent to each symbol, by symbol index
+	symbolParts [][]int
+}
+
+// The numbers adjacent to the symbol at the given index.
+func (s *Schematic) PartsAdjacentTo(symbol int) []PartNumber {
+	var parts []PartNumber
+	for _, part := range s.symbolParts[symbol] {
+		parts = append(parts, s.numbers[part])
+	}
+	return parts
+}
+
+// All numbers touching one of the given symbols, or any symbol if none are given.
+// Each number is included once, in the order it appears in the schematic.
+func (s *Schematic) PartsTouching(chars ...rune) []PartNumber {
+	touching := make([]bool, len(s.numbers))
+	for i, symbol := range s.symbols {
+		if len(chars) > 0 && !slices.Contains(chars, symbol.char) {
+			continue
+		}
+		for _, part := range s.symbolParts[i] {
+			touching[part] = true
+		}
+	}
+
+	var parts []PartNumber
+	for i, number := range s.numbers {
+		if touching[i] {
+			parts = append(parts, number)
+		}
+	}
+	return parts
+}
+
+// The indices of all symbols with exactly k adjacent numbers.
+func (s *Schematic) SymbolsWithNeighbors(k int) []int {
+	var symbols []int
+	for i, parts := range s.symbolParts {
+		if len(parts) == k {
+			symbols = append(symbols, i)
+		}
+	}
+	return symbols
 }
 
-func BuildMatrix(file string) ([][]any, error) {
+/********** File Functions **********/
+
+// Only ASCII digits make up part numbers, other digits are symbols like any other.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func BuildSchematic(file string) (*Schematic, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
 		return nil, err
 	}
-	var matrix [][]any
-	for scanner.Scan() {
-		line := make([]any, len(scanner.Text()))
-		var currentNumber string
-		for i, char := range scanner.Text() {
-			if unicode.IsDigit(char) {
-				currentNumber += string(char)
-				continue
-			}
-			if len(currentNumber) > 0 {
-				value, err := strconv.Atoi(currentNumber)
-				if err != nil {
-					return nil, err
-				}
-				for j := i - len(currentNumber); j < i; j++ {
-					line[j] = &value
+
+	schematic := &Schematic{}
+	// The index of the number covering each cell, or -1
+	var grid [][]int
+	for row := 0; scanner.Scan(); row++ {
+		line := []rune(scanner.Text())
+		cells := make([]int, len(line))
+		for col := 0; col < len(line); col++ {
+			cells[col] = -1
+			if !isDigit(line[col]) {
+				if line[col] != '.' {
+					schematic.symbols = append(schematic.symbols, Symbol{char: line[col], row: row, col: col})
 				}
-				currentNumber = ""
+				continue
 			}
-			if char != '.' {
-				line[i] = char
+
+			start := col
+			for col+1 < len(line) && isDigit(line[col+1]) {
+				col++
 			}
-		}
-		if len(currentNumber) > 0 {
-			value, err := strconv.Atoi(currentNumber)
+			value, err := strconv.Atoi(string(line[start : col+1]))
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("Row %v, column %v: %w", row+1, start+1, err)
 			}
-			for i := len(line) - len(currentNumber); i < len(line); i++ {
-				line[i] = &value
+			for i := start; i <= col; i++ {
+				cells[i] = len(schematic.numbers)
 			}
+			schematic.numbers = append(schematic.numbers, PartNumber{value: value, row: row, start: start, end: col + 1})
 		}
-		matrix = append(matrix, line)
+		grid = append(grid, cells)
+		schematic.lines = append(schematic.lines, line)
 	}
 
-	return matrix, nil
-}
-
-func getNumbersAdjacentToSymbols(matrix [][]any) []*int {
-	var uniqueNums []*int
-	for i, row := range matrix {
-		for j, cell := range row {
-			if _, ok := cell.(rune); ok {
-				// Look for all numbers around symbol
-				for m := max(0, i-1); m < min(len(matrix), i+2); m++ {
-					for n := max(0, j-1); n < min(len(matrix[m]), j+2); n++ {
-						if value, ok := matrix[m][n].(*int); ok && !slices.Contains(uniqueNums, value) {
-							uniqueNums = append(uniqueNums, value)
-						}
-					}
+	// Index the numbers around each symbol
+	for _, symbol := range schematic.symbols {
+		var parts []int
+		for m := max(0, symbol.row-1); m < min(len(grid), symbol.row+2); m++ {
+			for n := max(0, symbol.col-1); n < min(len(grid[m]), symbol.col+2); n++ {
+				if part := grid[m][n]; part != -1 && !slices.Contains(parts, part) {
+					parts = append(parts, part)
 				}
 			}
 		}
+		schematic.symbolParts = append(schematic.symbolParts, parts)
 	}
 
-	return uniqueNums
+	return schematic, nil
 }
 
-func getSumOfGearRatios(matrix [][]any) int {
-	var sum int
-	for i, row := range matrix {
-		for j, cell := range row {
-			if char, ok := cell.(rune); ok && char == '*' {
-				// Look for exactly 2 numbers beside the gear
-				var surroundingNums []*int
-				for m := max(0, i-1); m < min(len(matrix), i+2); m++ {
-					for n := max(0, j-1); n < min(len(matrix[m]), j+2); n++ {
-						if value, ok := matrix[m][n].(*int); ok && !slices.Contains(surroundingNums, value) {
-							surroundingNums = append(surroundingNums, value)
-						}
-					}
-				}
-				if len(surroundingNums) == 2 {
-					sum += (*surroundingNums[0] * *surroundingNums[1])
-				}
-			}
-		}
+/********** Gears **********/
+
+type CombineFunc func(values []int) int
+
+func product(values []int) int {
+	result := 1
+	for _, value := range values {
+		result *= value
 	}
-	return sum
+	return result
 }
 
-func checkMatrix(matrix [][]any, matched []*int) {
-	var unmatched []*int
-	for _, row := range matrix {
-		for _, cell := range row {
-			if value, ok := cell.(*int); ok && !slices.Contains(matched, value) && !slices.Contains(unmatched, value) {
-				unmatched = append(unmatched, value)
-			}
-		}
+func sum(values []int) int {
+	var result int
+	for _, value := range values {
+		result += value
 	}
+	return result
+}
 
-	fmt.Println("Matched values")
-	for _, val := range matched {
-		fmt.Printf("%v\n", *val)
+var combineFuncs = map[string]CombineFunc{
+	"product": product,
+	"sum":     sum,
+	"max":     slices.Max[[]int],
+}
+
+// Which symbols count as gears, and how their adjacent numbers make up a ratio.
+type GearRule struct {
+	symbols   []rune
+	neighbors int
+	// If set, gears need at least the given number of neighbors instead of exactly that many
+	atLeast bool
+	// The name of the function in combineFuncs
+	combine string
+}
+
+var DefaultGearRule = GearRule{symbols: []rune{'*'}, neighbors: 2, combine: "product"}
+
+func NewGearRule(symbols string, neighbors int, atLeast bool, combine string) (GearRule, error) {
+	if _, ok := combineFuncs[combine]; !ok {
+		return GearRule{}, fmt.Errorf("Unknown combine function %q, expected product, sum or max", combine)
+	}
+	if len(symbols) == 0 {
+		return GearRule{}, fmt.Errorf("Gear rule needs at least one symbol")
 	}
-	fmt.Println("\nUnmatched values")
-	for _, val := range unmatched {
-		fmt.Printf("%v\n", *val)
+	if neighbors < 1 {
+		return GearRule{}, fmt.Errorf("Gears need at least 1 neighbor, got %v", neighbors)
 	}
+	return GearRule{symbols: []rune(symbols), neighbors: neighbors, atLeast: atLeast, combine: combine}, nil
 }
 
-func part1(file string) error {
-	matrix, err := BuildMatrix(file)
-	if err != nil {
-		return err
+func (r GearRule) IsGear(schematic *Schematic, symbol int) bool {
+	if !slices.Contains(r.symbols, schematic.symbols[symbol].char) {
+		return false
+	}
+	neighbors := len(schematic.symbolParts[symbol])
+	if r.atLeast {
+		return neighbors >= r.neighbors
 	}
+	return neighbors == r.neighbors
+}
 
-	nums := getNumbersAdjacentToSymbols(matrix)
+func (r GearRule) Ratio(schematic *Schematic, symbol int) int {
+	var values []int
+	for _, part := range schematic.PartsAdjacentTo(symbol) {
+		values = append(values, part.value)
+	}
+	return combineFuncs[r.combine](values)
+}
+
+func getSumOfGearRatios(schematic *Schematic, rule GearRule) int {
 	var sum int
-	for _, num := range nums {
-		sum += *num
+	for i := range schematic.symbols {
+		if rule.IsGear(schematic, i) {
+			sum += rule.Ratio(schematic, i)
+		}
 	}
+	return sum
+}
 
-	// checkMatrix(matrix, nums)
-	fmt.Printf("The sum of numbers adjacent to symbols (part 1) is %v\n", sum)
+func part1(file string) (int, error) {
+	schematic, err := BuildSchematic(file)
+	if err != nil {
+		return 0, err
+	}
 
-	return nil
+	var sum int
+	for _, part := range schematic.PartsTouching() {
+		sum += part.value
+	}
+
+	return sum, nil
 }
 
-func part2(file string) error {
-	matrix, err := BuildMatrix(file)
+func part2(file string, rule GearRule) (int, error) {
+	schematic, err := BuildSchematic(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	sum := getSumOfGearRatios(matrix)
-
-	fmt.Printf("The sum of the gear ratios (part 1) is %v\n", sum)
-	return nil
+	return getSumOfGearRatios(schematic, rule), nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
+	colorFlag := flag.String("color", "auto", "whether to render with colors: auto, always or never")
+	gearSymbolsFlag := flag.String("gear-symbols", string(DefaultGearRule.symbols), "the symbols that can be gears in puzzle 2")
+	gearNeighborsFlag := flag.Int("gear-neighbors", DefaultGearRule.neighbors, "the number of adjacent numbers a gear needs in puzzle 2")
+	gearAtLeastFlag := flag.Bool("gear-at-least", DefaultGearRule.atLeast, "whether gears may have more than -gear-neighbors adjacent numbers")
+	gearCombineFlag := flag.String("gear-combine", DefaultGearRule.combine, "how to combine a gear's numbers into a ratio: product, sum or max")
 
-	return
+	var rule GearRule
+	runner.Day{
+		Number: 3,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, rule) }},
+		},
+		Tools: []runner.Tool{
+			{Name: "render", Usage: "whether to print the schematic with part numbers and gears highlighted", Run: func(file string) error {
+				return render(file, rule, *colorFlag)
+			}},
+		},
+		Setup: func() (err error) {
+			rule, err = NewGearRule(*gearSymbolsFlag, *gearNeighborsFlag, *gearAtLeastFlag, *gearCombineFlag)
+			return err
+		},
+	}.Main()
 }
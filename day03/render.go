@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/********** Rendering **********/
+
+type CellKind int
+
+const (
+	Empty CellKind = iota
+	PartDigit
+	IsolatedDigit
+	Gear
+	OtherSymbol
+)
+
+const ansiReset = "\033[0m"
+
+var cellColors = map[CellKind]string{
+	Empty:         "\033[2m",    // dim
+	PartDigit:     "\033[32m",   // green
+	IsolatedDigit: "\033[31m",   // red
+	Gear:          "\033[1;33m", // bold yellow
+	OtherSymbol:   "\033[36m",   // cyan
+}
+
+// Markers printed under each row when colors are not available.
+var cellMarkers = map[CellKind]rune{
+	Empty:         ' ',
+	PartDigit:     'P',
+	IsolatedDigit: 'x',
+	Gear:          'G',
+	OtherSymbol:   'S',
+}
+
+// The kind of every cell in the schematic, by row and column.
+func classifyCells(schematic *Schematic, rule GearRule) [][]CellKind {
+	kinds := make([][]CellKind, len(schematic.lines))
+	for row, line := range schematic.lines {
+		kinds[row] = make([]CellKind, len(line))
+	}
+
+	isPart := make(map[PartNumber]bool)
+	for _, part := range schematic.PartsTouching() {
+		isPart[part] = true
+	}
+	for _, number := range schematic.numbers {
+		kind := IsolatedDigit
+		if isPart[number] {
+			kind = PartDigit
+		}
+		for col := number.start; col < number.end; col++ {
+			kinds[number.row][col] = kind
+		}
+	}
+
+	for i, symbol := range schematic.symbols {
+		kind := OtherSymbol
+		if rule.IsGear(schematic, i) {
+			kind = Gear
+		}
+		kinds[symbol.row][symbol.col] = kind
+	}
+	return kinds
+}
+
+func renderColor(w io.Writer, schematic *Schematic, kinds [][]CellKind) {
+	for row, line := range schematic.lines {
+		var b strings.Builder
+		current := CellKind(-1)
+		for col, char := range line {
+			if kinds[row][col] != current {
+				current = kinds[row][col]
+				b.WriteString(ansiReset + cellColors[current])
+			}
+			b.WriteRune(char)
+		}
+		b.WriteString(ansiReset)
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+func renderPlain(w io.Writer, schematic *Schematic, kinds [][]CellKind) {
+	fmt.Fprintf(w, "Markers: %c part number, %c isolated number, %c gear, %c other symbol\n\n",
+		cellMarkers[PartDigit], cellMarkers[IsolatedDigit], cellMarkers[Gear], cellMarkers[OtherSymbol])
+	for row, line := range schematic.lines {
+		markers := make([]rune, len(line))
+		for col := range line {
+			markers[col] = cellMarkers[kinds[row][col]]
+		}
+		fmt.Fprintln(w, string(line))
+		fmt.Fprintln(w, strings.TrimRight(string(markers), " "))
+	}
+}
+
+// Whether colors should be used for the color mode "always", "never" or "auto".
+func useColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		if err != nil {
+			return false, err
+		}
+		return info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("Unknown color mode %q, expected auto, always or never", mode)
+	}
+}
+
+func render(file string, rule GearRule, colorMode string) error {
+	schematic, err := BuildSchematic(file)
+	if err != nil {
+		return err
+	}
+	color, err := useColor(colorMode, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	kinds := classifyCells(schematic, rule)
+	if color {
+		renderColor(os.Stdout, schematic, kinds)
+	} else {
+		renderPlain(os.Stdout, schematic, kinds)
+	}
+	return nil
+}
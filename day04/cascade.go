@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+/********** Copy Cascade **********/
+
+// Copies of a card that were won by holding copies of another card.
+type CopySource struct {
+	fromId int
+	copies int64
+}
+
+type Cascade struct {
+	// The cards in order of id
+	cards []*Scratchcard
+	// The number of copies of each card, including the original, by card index
+	copies []int64
+	// The cards that produced copies of each card, by card index
+	sources [][]CopySource
+	total   int64
+}
+
+func addCopies(a, b int64) (int64, error) {
+	if a > math.MaxInt64-b {
+		return 0, fmt.Errorf("Number of copies overflows int64 (%v + %v)", a, b)
+	}
+	return a + b, nil
+}
+
+// Checks that the card ids are contiguous, and if ordered is set that they are in increasing order.
+func ValidateCardIds(cards []*Scratchcard, ordered bool) error {
+	if len(cards) == 0 {
+		return nil
+	}
+	firstId := slices.MinFunc(cards, func(a, b *Scratchcard) int { return a.id - b.id }).id
+	seen := make([]bool, len(cards))
+	for i, card := range cards {
+		if ordered && card.id != cards[0].id+i {
+			return fmt.Errorf("Expected card %v on line %v, got card %v (use -by-id to accept unordered cards)", cards[0].id+i, i+1, card.id)
+		}
+		index := card.id - firstId
+		if index >= len(cards) {
+			return fmt.Errorf("Card ids are not contiguous: card %v is past the %v cards starting at card %v", card.id, len(cards), firstId)
+		}
+		if seen[index] {
+			return fmt.Errorf("Card %v appears more than once", card.id)
+		}
+		seen[index] = true
+	}
+	return nil
+}
+
+// Plays out the cards, where each card wins one copy of the next cards by id for every match.
+// Unless byId is set, the cards must already be in order of id.
+func GetCascade(cards []*Scratchcard, byId bool) (*Cascade, error) {
+	if err := ValidateCardIds(cards, !byId); err != nil {
+		return nil, err
+	}
+	if byId {
+		cards = slices.Clone(cards)
+		slices.SortFunc(cards, func(a, b *Scratchcard) int { return a.id - b.id })
+	}
+
+	cascade := &Cascade{cards: cards, copies: make([]int64, len(cards)), sources: make([][]CopySource, len(cards))}
+	for i := range cascade.copies {
+		cascade.copies[i] = 1
+	}
+
+	var err error
+	for i, card := range cards {
+		copies := cascade.copies[i]
+		for j := i + 1; j < min(i+1+card.GetNumberOfMatches(), len(cards)); j++ {
+			if cascade.copies[j], err = addCopies(cascade.copies[j], copies); err != nil {
+				return nil, fmt.Errorf("Card %v: %w", cards[j].id, err)
+			}
+			cascade.sources[j] = append(cascade.sources[j], CopySource{fromId: card.id, copies: copies})
+		}
+		if cascade.total, err = addCopies(cascade.total, copies); err != nil {
+			return nil, fmt.Errorf("Total cards: %w", err)
+		}
+	}
+	return cascade, nil
+}
+
+// The number of copies held of every card, by card id.
+func (c *Cascade) CopiesById() map[int]int64 {
+	copies := make(map[int]int64, len(c.cards))
+	for i, card := range c.cards {
+		copies[card.id] = c.copies[i]
+	}
+	return copies
+}
+
+// The cards that produced copies of the card at the given index.
+func (c *Cascade) SourcesOf(index int) []CopySource {
+	return c.sources[index]
+}
+
+func (c *Cascade) Total() int64 {
+	return c.total
+}
+
+func (c *Cascade) WriteBreakdown(w io.Writer) {
+	for i, card := range c.cards {
+		parts := []string{"1 original"}
+		for _, source := range c.SourcesOf(i) {
+			parts = append(parts, fmt.Sprintf("%v from card %v", source.copies, source.fromId))
+		}
+		fmt.Fprintf(w, "Card %v: %v copies (%v)\n", card.id, c.copies[i], strings.Join(parts, ", "))
+	}
+}
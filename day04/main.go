@@ -3,138 +3,142 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
-	"math"
-	"slices"
-	"strconv"
-	"strings"
+	"os"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 type Scratchcard struct {
 	id               int
 	winningNumbers   []int
 	scratchedNumbers []int
+	matches          int
 }
 
-func (s *Scratchcard) GetNumberOfMatches() int {
+// Builds a card and counts its matches, rejecting repeated numbers within either section.
+func NewScratchcard(id int, winningNumbers, scratchedNumbers []int) (*Scratchcard, error) {
+	winningSet, repeated := toSet(winningNumbers)
+	if repeated != nil {
+		return nil, fmt.Errorf("Card %v has a repeated winning number %v", id, *repeated)
+	}
+	if _, repeated := toSet(scratchedNumbers); repeated != nil {
+		return nil, fmt.Errorf("Card %v has a repeated scratched number %v", id, *repeated)
+	}
+
 	var matches int
-	for _, scratchedNum := range s.scratchedNumbers {
-		if slices.Contains(s.winningNumbers, scratchedNum) {
+	for _, scratchedNum := range scratchedNumbers {
+		if winningSet[scratchedNum] {
 			matches++
 		}
 	}
-	return matches
+	return &Scratchcard{id: id, winningNumbers: winningNumbers, scratchedNumbers: scratchedNumbers, matches: matches}, nil
 }
 
-func parseSpaceSeparatedNumbers(s string) ([]int, error) {
-	var nums []int
-	numberParts := strings.Split(strings.TrimSpace(s), " ")
-	for _, numberPart := range numberParts {
-		if len(numberPart) == 0 {
-			continue
-		}
-		value, err := strconv.Atoi(numberPart)
-		if err != nil {
-			return nil, fmt.Errorf("Error parsing number %q in string %q: %w", numberPart, s, err)
+func (s *Scratchcard) GetNumberOfMatches() int {
+	return s.matches
+}
+
+// Returns the set of numbers, or the first number that appears more than once.
+func toSet(nums []int) (map[int]bool, *int) {
+	set := make(map[int]bool, len(nums))
+	for _, num := range nums {
+		if set[num] {
+			return nil, &num
 		}
-		nums = append(nums, value)
+		set[num] = true
 	}
-	return nums, nil
+	return set, nil
 }
 
+// card = "Card" id ":" number... "|" number...
 func readCard(line string) (*Scratchcard, error) {
-	lineParts := strings.Split(line, ":")
-	if len(lineParts) != 2 {
-		return nil, fmt.Errorf("Expected line to have 1 colon, got %v", len(lineParts)-1)
+	var cardId int
+	var winningNumbers, scratchedNumbers []int
+	grammar := parser.Sequence(
+		parser.Literal("Card"),
+		parser.RequiredSpaces(),
+		parser.Into(parser.Int(), &cardId),
+		parser.Literal(":"),
+		parser.Into(parser.Ints(), &winningNumbers),
+		parser.Literal("|"),
+		parser.Into(parser.Ints(), &scratchedNumbers),
+	)
+	if _, err := parser.Parse(grammar, line); err != nil {
+		return nil, fmt.Errorf("Error parsing card %q: %w", line, err)
 	}
-	cardPart := lineParts[0]
-	numbersPart := lineParts[1]
 
-	cardIdPart := strings.TrimPrefix(cardPart, "Card ")
-	cardId, err := strconv.Atoi(strings.TrimSpace(cardIdPart))
-	if err != nil {
-		return nil, fmt.Errorf("Could not parse card id: %w", err)
-	}
+	return NewScratchcard(cardId, winningNumbers, scratchedNumbers)
+}
 
-	sectionParts := strings.Split(numbersPart, " | ")
-	if len(sectionParts) != 2 {
-		return nil, fmt.Errorf("Expected line to have 1 bar, got %v", len(sectionParts)-1)
-	}
-	winningNumbers, err := parseSpaceSeparatedNumbers(sectionParts[0])
+func part1(file string, scheme ScoringScheme, workers int) (int, error) {
+	cards, err := fileReader.ReadFileByLineParallel(file, workers, fileReader.IgnoreLineNumber(readCard))
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	scratchedNumbers, err := parseSpaceSeparatedNumbers(sectionParts[1])
+
+	sum, err := getTotalPoints(cards, scheme)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &Scratchcard{id: cardId, winningNumbers: winningNumbers, scratchedNumbers: scratchedNumbers}, nil
+	return sum, nil
 }
 
-func part1(file string) error {
-	cards, err := fileReader.ReadFileByLine(file, readCard)
+func part2(file string, byId bool, workers int) (int64, error) {
+	cards, err := fileReader.ReadFileByLineParallel(file, workers, fileReader.IgnoreLineNumber(readCard))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var sum int
-	for _, card := range cards {
-		points := int(math.Floor(math.Pow(2, float64(card.GetNumberOfMatches()-1))))
-		sum += points
+	cascade, err := GetCascade(cards, byId)
+	if err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("The scratchcards have a total of %v points\n", sum)
-	return nil
+	return cascade.Total(), nil
 }
 
-func part2(file string) error {
-	cards, err := fileReader.ReadFileByLine(file, readCard)
+func breakdown(file string, byId bool, workers int) error {
+	cards, err := fileReader.ReadFileByLineParallel(file, workers, fileReader.IgnoreLineNumber(readCard))
 	if err != nil {
 		return err
 	}
 
-	cardCopies := make([]int64, len(cards))
-	for i := range cardCopies {
-		cardCopies[i] = 1
-	}
-
-	var totalCopies int64
-	for i, card := range cards {
-		matches := card.GetNumberOfMatches()
-		copies := cardCopies[i]
-		for j := i + 1; j < min(i+1+matches, len(cardCopies)); j++ {
-			cardCopies[j] += copies
-		}
-		totalCopies += copies
+	cascade, err := GetCascade(cards, byId)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("There are a total of %v cards in part 2\n", totalCopies)
+	cascade.WriteBreakdown(os.Stdout)
 	return nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	return
+	workersFlag := flag.Int("workers", 0, "the number of lines to parse at once, defaults to one per CPU")
+	byIdFlag := flag.Bool("by-id", false, "whether to accept cards in any order in puzzle 2, matching copies by card id")
+	scoringFlag := flag.String("scoring", "doubling", "how matches are scored: doubling, linear or table")
+	scoreTableFlag := flag.String("score-table", "0,1,2,4,8,16", "the comma separated points for 0, 1, 2... matches when using -scoring table")
+
+	var scheme ScoringScheme
+	runner.Day{
+		Number: 4,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, scheme, *workersFlag) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, *byIdFlag, *workersFlag) }},
+		},
+		Tools: []runner.Tool{
+			{Name: "breakdown", Usage: "whether to print where the copies of each card came from in puzzle 2", Run: func(file string) error {
+				return breakdown(file, *byIdFlag, *workersFlag)
+			}},
+			{Name: "histogram", Usage: "whether to print how many cards have each number of matches", Run: func(file string) error {
+				return histogram(file, scheme)
+			}},
+		},
+		Setup: func() (err error) {
+			scheme, err = getScoringScheme(*scoringFlag, *scoreTableFlag)
+			return err
+		},
+	}.Main()
 }
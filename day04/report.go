@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+)
+
+/********** Histogram **********/
+
+type MatchBucket struct {
+	cards, points int
+}
+
+// The number of cards and their total points for every match count from 0 to the highest seen.
+func getMatchHistogram(cards []*Scratchcard, scheme ScoringScheme) ([]MatchBucket, error) {
+	var buckets []MatchBucket
+	for _, card := range cards {
+		matches := card.GetNumberOfMatches()
+		for len(buckets) <= matches {
+			buckets = append(buckets, MatchBucket{})
+		}
+		points, err := scheme(matches)
+		if err != nil {
+			return nil, fmt.Errorf("Card %v: %w", card.id, err)
+		}
+		buckets[matches].cards++
+		if buckets[matches].points, err = addPoints(buckets[matches].points, points); err != nil {
+			return nil, err
+		}
+	}
+	return buckets, nil
+}
+
+func histogram(file string, scheme ScoringScheme) error {
+	cards, err := fileReader.ReadFileByLine(file, readCard)
+	if err != nil {
+		return err
+	}
+
+	buckets, err := getMatchHistogram(cards, scheme)
+	if err != nil {
+		return err
+	}
+	var mostCards int
+	for _, bucket := range buckets {
+		mostCards = max(mostCards, bucket.cards)
+	}
+
+	const barWidth = 40
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "matches\tcards\tpoints\t")
+	var totalCards, totalPoints int
+	for matches, bucket := range buckets {
+		bar := strings.Repeat("#", bucket.cards*barWidth/max(mostCards, 1))
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", matches, bucket.cards, bucket.points, bar)
+		totalCards += bucket.cards
+		if totalPoints, err = addPoints(totalPoints, bucket.points); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(tw, "total\t%v\t%v\t\n", totalCards, totalPoints)
+	return tw.Flush()
+}
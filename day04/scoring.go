@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/********** Scoring **********/
+
+// Turns a card's number of matches into points.
+type ScoringScheme func(matches int) (int, error)
+
+// One point for the first match, doubled for every match after it.
+func doublingScore(matches int) (int, error) {
+	if matches == 0 {
+		return 0, nil
+	}
+	if matches-1 >= bits.UintSize-1 {
+		return 0, fmt.Errorf("Doubling score for %v matches overflows int", matches)
+	}
+	return 1 << (matches - 1), nil
+}
+
+// One point for every match.
+func linearScore(matches int) (int, error) {
+	return matches, nil
+}
+
+// The points for n matches are at index n of the table.
+func tableScore(table []int) ScoringScheme {
+	return func(matches int) (int, error) {
+		if matches >= len(table) {
+			return 0, fmt.Errorf("Score table has no entry for %v matches", matches)
+		}
+		return table[matches], nil
+	}
+}
+
+// Gets the scheme "doubling", "linear" or "table", where the table is a comma separated list of points.
+func getScoringScheme(name, table string) (ScoringScheme, error) {
+	switch name {
+	case "doubling":
+		return doublingScore, nil
+	case "linear":
+		return linearScore, nil
+	case "table":
+		var points []int
+		for _, entry := range strings.Split(table, ",") {
+			value, err := strconv.Atoi(strings.TrimSpace(entry))
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing score table: %w", err)
+			}
+			points = append(points, value)
+		}
+		return tableScore(points), nil
+	default:
+		return nil, fmt.Errorf("Unknown scoring scheme %q, expected doubling, linear or table", name)
+	}
+}
+
+func addPoints(a, b int) (int, error) {
+	if (b > 0 && a > math.MaxInt-b) || (b < 0 && a < math.MinInt-b) {
+		return 0, fmt.Errorf("Sum of points overflows int (%v + %v)", a, b)
+	}
+	return a + b, nil
+}
+
+// The total points of all cards under the given scheme.
+func getTotalPoints(cards []*Scratchcard, scheme ScoringScheme) (int, error) {
+	var total int
+	for _, card := range cards {
+		points, err := scheme(card.GetNumberOfMatches())
+		if err != nil {
+			return 0, fmt.Errorf("Card %v: %w", card.id, err)
+		}
+		if total, err = addPoints(total, points); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
@@ -2,15 +2,18 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
-	"log"
 	"math"
+	"os"
+	"os/signal"
 	"slices"
-	"strconv"
-	"strings"
+	"time"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 /********** Types **********/
@@ -45,12 +48,14 @@ type Mapper[S ~int, D ~int] struct {
 	length      int
 }
 
+// The last destination in the mapper's range.
 func (m *Mapper[S, D]) destinationEnd() D {
-	return m.destination + D(m.length)
+	return m.destination + D(m.length) - 1
 }
 
+// The last source in the mapper's range.
 func (m *Mapper[S, D]) sourceEnd() S {
-	return m.source + S(m.length)
+	return m.source + S(m.length) - 1
 }
 
 func (m *Mapper[S, D]) GetDestination(source S) (D, bool) {
@@ -141,21 +146,49 @@ func CombineIndividualMappers[S ~int, I ~int, D ~int](first *Mapper[S, I], secon
 	return combinedMapper, unmatchedSources, unmatchedDests
 }
 
+// Combines start with the ends in turn, where each end's source is disjoint from the others'.
+// Returns the combined mappers covering start's source, and the parts of ends start didn't reach.
 func FlattenMapper[S ~int, I ~int, D ~int](start *Mapper[S, I], ends []*Mapper[I, D]) ([]*Mapper[S, D], []*Mapper[I, D]) {
 	if len(ends) == 0 {
 		return []*Mapper[S, D]{{source: start.source, destination: D(start.destination), length: start.length}}, nil
 	}
 	var finalMappers []*Mapper[S, D]
-	var dests []*Mapper[I, D]
 	combined, unmatchedSources, unmatchedDests := CombineIndividualMappers(start, ends[0])
-	finalMappers = append(finalMappers, combined)
-	dests = append(dests, unmatchedDests...)
+	if combined != nil {
+		finalMappers = append(finalMappers, combined)
+	}
+	// The unmatched parts of start can only meet the remaining ends
+	rest := ends[1:]
 	for _, unmatchedSource := range unmatchedSources {
-		c, d := FlattenMapper(unmatchedSource, ends[1:])
+		var c []*Mapper[S, D]
+		c, rest = FlattenMapper(unmatchedSource, rest)
 		finalMappers = append(finalMappers, c...)
-		unmatchedDests = append(unmatchedDests, d...)
 	}
-	return finalMappers, unmatchedDests
+	return finalMappers, append(unmatchedDests, rest...)
+}
+
+// The parts of m whose sources are outside every source range in covered.
+func uncoveredParts[S ~int, I ~int, D ~int](m *Mapper[I, D], covered []*Mapper[S, I]) []*Mapper[I, D] {
+	parts := []*Mapper[I, D]{m}
+	for _, c := range covered {
+		low, high := int(c.source), int(c.sourceEnd())
+		var next []*Mapper[I, D]
+		for _, p := range parts {
+			if int(p.sourceEnd()) < low || int(p.source) > high {
+				next = append(next, p)
+				continue
+			}
+			if int(p.source) < low {
+				next = append(next, &Mapper[I, D]{source: p.source, destination: p.destination, length: low - int(p.source)})
+			}
+			if int(p.sourceEnd()) > high {
+				len := int(p.sourceEnd()) - high
+				next = append(next, &Mapper[I, D]{source: p.sourceEnd() - I(len) + 1, destination: p.destinationEnd() - D(len) + 1, length: len})
+			}
+		}
+		parts = next
+	}
+	return parts
 }
 
 func FlattenMappers[S ~int, I ~int, D ~int](starts []*Mapper[S, I], ends []*Mapper[I, D]) []*Mapper[S, D] {
@@ -165,8 +198,11 @@ func FlattenMappers[S ~int, I ~int, D ~int](starts []*Mapper[S, I], ends []*Mapp
 		finalMappers = append(finalMappers, c...)
 		ends = d
 	}
+	// Sources no start maps pass through unchanged, so the remaining ends apply to them directly
 	for _, end := range ends {
-		finalMappers = append(finalMappers, &Mapper[S, D]{source: S(end.source), destination: end.destination, length: end.length})
+		for _, part := range uncoveredParts(end, starts) {
+			finalMappers = append(finalMappers, &Mapper[S, D]{source: S(part.source), destination: part.destination, length: part.length})
+		}
 	}
 	return finalMappers
 }
@@ -191,7 +227,7 @@ func FlattenAlmanac(a *Almanac) []*Mapper[Seed, Location] {
 /********** File Functions **********/
 
 const (
-	seedsPrefix        = "seeds: "
+	seedsPrefix        = "seeds:"
 	seedToSoil         = "seed-to-soil map:"
 	soilToFertilizer   = "soil-to-fertilizer map:"
 	fertilizerToWater  = "fertilizer-to-water map:"
@@ -201,38 +237,36 @@ const (
 	humidityToLocation = "humidity-to-location map:"
 )
 
+// seeds = "seeds:" number...
 func readSeedsLine(line string) ([]Seed, error) {
-	seedParts := strings.Split(strings.TrimPrefix(line, seedsPrefix), " ")
-	var seeds []Seed
-	for _, seedPart := range seedParts {
-		value, err := strconv.Atoi(seedPart)
-		if err != nil {
-			return nil, fmt.Errorf("Error parsing seed: %w", err)
-		}
-		seeds = append(seeds, Seed(value))
+	var values []int
+	grammar := parser.Sequence(
+		parser.Literal(seedsPrefix),
+		parser.Into(parser.Ints(), &values),
+	)
+	if _, err := parser.Parse(grammar, line); err != nil {
+		return nil, fmt.Errorf("Error parsing seeds %q: %w", line, err)
+	}
+
+	seeds := make([]Seed, len(values))
+	for i, value := range values {
+		seeds[i] = Seed(value)
 	}
 	return seeds, nil
 }
 
+// mapper = destination source length
 func readMapperLine[S ~int, D ~int](line string) (*Mapper[S, D], error) {
-	parts := strings.Split(line, " ")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("Expected 3 numbers in line %q, got %v", line, len(parts))
-	}
-
-	dest, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return nil, fmt.Errorf("Error parsing destination: %w", err)
-	}
-
-	source, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return nil, fmt.Errorf("Error parsing source: %w", err)
-	}
-
-	len, err := strconv.Atoi(parts[2])
-	if err != nil {
-		return nil, fmt.Errorf("Error parsing length: %w", err)
+	var dest, source, len int
+	grammar := parser.Sequence(
+		parser.Into(parser.Int(), &dest),
+		parser.RequiredSpaces(),
+		parser.Into(parser.Int(), &source),
+		parser.RequiredSpaces(),
+		parser.Into(parser.Int(), &len),
+	)
+	if _, err := parser.Parse(grammar, line); err != nil {
+		return nil, fmt.Errorf("Error parsing mapper %q: %w", line, err)
 	}
 
 	return &Mapper[S, D]{source: S(source), destination: D(dest), length: len}, nil
@@ -317,14 +351,14 @@ func readAlmanacFile(scanner *bufio.Scanner) ([]Seed, *Almanac, error) {
 
 /********** Main Functions **********/
 
-func part1(file string) error {
+func part1(file string) (int, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	seeds, almanac, err := readAlmanacFile(scanner)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	minLocation := math.MaxInt
@@ -334,18 +368,17 @@ func part1(file string) error {
 		minLocation = min(minLocation, int(seedValues.loc))
 	}
 
-	fmt.Printf("The minimum seed location in part 1 is %v\n", minLocation)
-	return nil
+	return minLocation, nil
 }
 
-func part1v2(file string) error {
+func part1v2(file string) (int, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	seeds, almanac, err := readAlmanacFile(scanner)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	mappers := FlattenAlmanac(almanac)
@@ -353,62 +386,60 @@ func part1v2(file string) error {
 	minLocation := math.MaxInt
 	for _, seed := range seeds {
 		loc := GetDestinationFromMappers(seed, mappers)
-		fmt.Printf("Seed %v, location %v\n", seed, loc)
 		minLocation = min(minLocation, int(loc))
 	}
 
-	fmt.Printf("The minimum seed location in part 1 is %v\n", minLocation)
-	return nil
+	return minLocation, nil
 }
 
 // Brute force got 12634632
-func part2(file string) error {
+func part2(file string, workers int, showProgress bool) (int, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	seedNums, almanac, err := readAlmanacFile(scanner)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if len(seedNums)%2 != 0 {
-		return fmt.Errorf("Expected even number of seeds, got %v", len(seedNums))
-	}
-	minLocation := math.MaxInt
-	for i := 0; i < len(seedNums); i += 2 {
-		// Iterate, where index i is the start number and i+1 is the number of cycles
-		for seed := seedNums[i]; seed < seedNums[i]+seedNums[i+1]; seed++ {
-			seedValues := almanac.FindSeedValues(seed)
-			// fmt.Println(seedValues.String())
-			minLocation = min(minLocation, int(seedValues.loc))
-		}
+	// Iterate over pairs, where index i is the start number and i+1 is the number of cycles
+	chunks, err := chunkSeedRanges(seedNums, 1<<20)
+	if err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("The minimum seed location in part 2 is %v\n", minLocation)
-	return nil
-}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	progress := NewSearchProgress(chunks)
+	if showProgress {
+		progressCtx, stopProgress := context.WithCancel(ctx)
+		defer stopProgress()
+		go reportProgress(progressCtx, os.Stderr, progress, time.Second)
+	}
 
-func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
+	minLocation, err := findMinLocationParallel(ctx, almanac, chunks, workers, progress)
+	if showProgress && time.Since(progress.started) >= time.Second {
+		// Clear the progress line
+		fmt.Fprintf(os.Stderr, "\r\033[K")
 	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
+	if err != nil {
+		return 0, fmt.Errorf("Search stopped after %v, minimum location so far is %v: %w", progress, minLocation, err)
 	}
 
-	return
+	return minLocation, nil
+}
+
+func main() {
+	workersFlag := flag.Int("workers", 0, "the number of goroutines searching seeds in puzzle 2, defaults to one per CPU")
+	progressFlag := flag.Bool("progress", true, "whether to show search progress for puzzle 2")
+
+	runner.Day{
+		Number: 5,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file) }},
+			{Name: "1v2", Run: func(file string) (any, error) { return part1v2(file) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, *workersFlag, *progressFlag) }, Slow: true},
+		},
+	}.Main()
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+)
+
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part1("input.txt"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart1v2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part1v2("input.txt"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Part 2 searches every seed and takes hours on input.txt, so it is benchmarked on the example.
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part2("example.txt", 0, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func readTestAlmanac(t *testing.T, file string) ([]Seed, *Almanac) {
+	t.Helper()
+	scanner, err := fileReader.GetFileScanner(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeds, almanac, err := readAlmanacFile(scanner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seeds, almanac
+}
+
+func TestPart1v2MatchesPart1(t *testing.T) {
+	for _, file := range []string{"example.txt", "input.txt"} {
+		want, err := part1(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := part1v2(file); err != nil || got != want {
+			t.Errorf("%v: got %v, %v, expected %v", file, got, err, want)
+		}
+	}
+}
+
+// Checks the flattened mappers against looking up each map in turn, at the edges of every
+// flattened and seed-to-soil range where an off by one would show.
+func TestFlattenAlmanac(t *testing.T) {
+	for _, file := range []string{"example.txt", "input.txt"} {
+		seeds, almanac := readTestAlmanac(t, file)
+		mappers := FlattenAlmanac(almanac)
+
+		bySource := slices.Clone(mappers)
+		slices.SortFunc(bySource, func(a, b *Mapper[Seed, Location]) int {
+			return int(a.source - b.source)
+		})
+		for i := 1; i < len(bySource); i++ {
+			if bySource[i].source <= bySource[i-1].sourceEnd() {
+				t.Errorf("%v: flattened ranges %v and %v overlap", file, *bySource[i-1], *bySource[i])
+			}
+		}
+
+		samples := append([]Seed{0, 1}, seeds...)
+		for _, m := range mappers {
+			samples = append(samples, m.source-1, m.source, m.sourceEnd(), m.sourceEnd()+1)
+		}
+		for _, m := range almanac.soilMaps {
+			samples = append(samples, m.source-1, m.source, m.sourceEnd(), m.sourceEnd()+1)
+		}
+		for _, seed := range samples {
+			if seed < 0 {
+				continue
+			}
+			want := almanac.FindSeedValues(seed).loc
+			if got := GetDestinationFromMappers(seed, mappers); got != want {
+				t.Errorf("%v: seed %v got location %v, expected %v", file, seed, got, want)
+			}
+		}
+	}
+}
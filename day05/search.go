@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/********** Parallel Brute Force **********/
+
+type SeedRange struct {
+	start  Seed
+	length int
+}
+
+// Splits pairs of (start, length) seed numbers into ranges of at most chunkSize seeds.
+func chunkSeedRanges(seedNums []Seed, chunkSize int) ([]SeedRange, error) {
+	if len(seedNums)%2 != 0 {
+		return nil, fmt.Errorf("Expected even number of seeds, got %v", len(seedNums))
+	}
+	var chunks []SeedRange
+	for i := 0; i < len(seedNums); i += 2 {
+		start, length := seedNums[i], int(seedNums[i+1])
+		for offset := 0; offset < length; offset += chunkSize {
+			chunks = append(chunks, SeedRange{start: start + Seed(offset), length: min(chunkSize, length-offset)})
+		}
+	}
+	return chunks, nil
+}
+
+type SearchProgress struct {
+	processed atomic.Int64
+	total     int64
+	started   time.Time
+}
+
+func NewSearchProgress(chunks []SeedRange) *SearchProgress {
+	progress := &SearchProgress{started: time.Now()}
+	for _, chunk := range chunks {
+		progress.total += int64(chunk.length)
+	}
+	return progress
+}
+
+func (p *SearchProgress) String() string {
+	processed := p.processed.Load()
+	elapsed := time.Since(p.started)
+	rate := float64(processed) / elapsed.Seconds()
+	eta := "unknown"
+	if rate > 0 {
+		eta = time.Duration(float64(p.total-processed) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+	return fmt.Sprintf("%v/%v seeds (%.1f%%), %.0f seeds/s, %v left", processed, p.total, 100*float64(processed)/float64(p.total), rate, eta)
+}
+
+// Writes the progress to w every interval until the context is done.
+func reportProgress(ctx context.Context, w io.Writer, progress *SearchProgress, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Fprintf(w, "\r\033[K%v", progress)
+		}
+	}
+}
+
+// Finds the minimum location of all seeds in the chunks, split across the given number of workers
+// (or one per CPU if not positive). If the context is cancelled, the minimum found so far is
+// returned with the context's error.
+func findMinLocationParallel(ctx context.Context, almanac *Almanac, chunks []SeedRange, workers int, progress *SearchProgress) (int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	work := make(chan SeedRange)
+	minimums := make([]int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		minimums[w] = math.MaxInt
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for chunk := range work {
+				for seed := chunk.start; seed < chunk.start+Seed(chunk.length); seed++ {
+					minimums[w] = min(minimums[w], int(almanac.FindSeedValues(seed).loc))
+				}
+				progress.processed.Add(int64(chunk.length))
+			}
+		}(w)
+	}
+
+sendChunks:
+	for _, chunk := range chunks {
+		select {
+		case work <- chunk:
+		case <-ctx.Done():
+			break sendChunks
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	minLocation := math.MaxInt
+	for _, m := range minimums {
+		minLocation = min(minLocation, m)
+	}
+	return minLocation, ctx.Err()
+}
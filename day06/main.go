@@ -2,14 +2,13 @@ package main
 
 import (
 	"bufio"
-	"flag"
 	"fmt"
-	"log"
 	"sort"
 	"strconv"
 	"strings"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 type Race struct {
@@ -93,64 +92,48 @@ func readRaceFile(scanner *bufio.Scanner, ignoreSpaces bool) ([]*Race, error) {
 	return races, nil
 }
 
-func part1(file string) error {
+func part1(file string, strategies func(*Race) int) (int, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	races, err := readRaceFile(scanner, false)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	product := 1
 	for _, race := range races {
-		product = product * winningStrategiesBinarySearch(race)
+		product = product * strategies(race)
 	}
 
-	fmt.Printf("The product of winning strategies in part 1 is %v\n", product)
-	return nil
+	return product, nil
 }
 
-func part2(file string) error {
+func part2(file string, strategies func(*Race) int) (int, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	races, err := readRaceFile(scanner, true)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if len(races) != 1 {
-		return fmt.Errorf("Expected one race, got %v", len(races))
+		return 0, fmt.Errorf("Expected one race, got %v", len(races))
 	}
 
-	strategies := winningStrategiesBinarySearch(races[0])
-
-	fmt.Printf("The number of winning strategies in part 2 is %v\n", strategies)
-	return nil
+	return strategies(races[0]), nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	return
+	runner.Day{
+		Number: 6,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, winningStrategiesBinarySearch) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, winningStrategiesBinarySearch) }},
+			{Name: "1linear", Run: func(file string) (any, error) { return part1(file, winningStrategies) }},
+			{Name: "2linear", Run: func(file string) (any, error) { return part2(file, winningStrategies) }},
+		},
+	}.Main()
 }
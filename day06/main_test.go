@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part1("input.txt", winningStrategiesBinarySearch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part2("input.txt", winningStrategiesBinarySearch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart1Linear(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part1("input.txt", winningStrategies); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPart2Linear(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := part2("input.txt", winningStrategies); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
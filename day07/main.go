@@ -3,12 +3,13 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
 	"sort"
 	"strconv"
 	"unicode"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 type HandType int
@@ -41,17 +42,25 @@ func (h *Hand) IsBetterThan(o *Hand) bool {
 	return false
 }
 
+func fiveCards(cards string) (string, error) {
+	if len(cards) != 5 {
+		return "", fmt.Errorf("expected 5 cards, got %v", len(cards))
+	}
+	return cards, nil
+}
+
 func getHandReader(jokers bool) func(line string) (*Hand, error) {
 	return func(line string) (*Hand, error) {
-		if len(line) < 7 {
-			return nil, fmt.Errorf("Line is too short for a hand %q", line)
-		}
-		cardString := line[0:5]
-		bidString := line[6:]
-
-		bid, err := strconv.Atoi(bidString)
-		if err != nil {
-			return nil, fmt.Errorf("Error parsing bid: %w", err)
+		// hand = cards bid
+		var cardString string
+		var bid int
+		grammar := parser.Sequence(
+			parser.Into(parser.Map(parser.Word(), fiveCards), &cardString),
+			parser.RequiredSpaces(),
+			parser.Into(parser.Int(), &bid),
+		)
+		if _, err := parser.Parse(grammar, line); err != nil {
+			return nil, fmt.Errorf("Error parsing hand %q: %w", line, err)
 		}
 
 		var cards [5]int
@@ -148,50 +157,36 @@ func getTotalWinnings(hands []*Hand) int {
 	return totalWinnings
 }
 
-func part1(file string) error {
-	hands, err := fileReader.ReadFileByLine(file, getHandReader(false))
+func part1(file string, workers int) (int, error) {
+	hands, err := fileReader.ReadFileByLineParallel(file, workers, fileReader.IgnoreLineNumber(getHandReader(false)))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	totalWinnings := getTotalWinnings(hands)
 
-	fmt.Printf("The total winnings from part 1 are %v\n", totalWinnings)
-	return nil
+	return totalWinnings, nil
 }
 
-func part2(file string) error {
-	hands, err := fileReader.ReadFileByLine(file, getHandReader(true))
+func part2(file string, workers int) (int, error) {
+	hands, err := fileReader.ReadFileByLineParallel(file, workers, fileReader.IgnoreLineNumber(getHandReader(true)))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	totalWinnings := getTotalWinnings(hands)
 
-	fmt.Printf("The total winnings from part 2 are %v\n", totalWinnings)
-	return nil
+	return totalWinnings, nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	return
+	workersFlag := flag.Int("workers", 0, "the number of lines to parse at once, defaults to one per CPU")
+
+	runner.Day{
+		Number: 7,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, *workersFlag) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, *workersFlag) }},
+		},
+	}.Main()
 }
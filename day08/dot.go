@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+/********** Graphviz Export **********/
+
+type pathStats struct {
+	// The step at which each node was first reached, by id
+	firstStep map[int]int
+	// How many times each edge was taken, keyed by the id it was taken from and its direction
+	edgeCounts map[edgeKey]int
+}
+
+type edgeKey struct {
+	from      int
+	direction byte
+}
+
+// Combines the paths of several walks, taking the earliest step any of them reached each node.
+func getPathStats(network *Network, paths ...[]int) *pathStats {
+	stats := &pathStats{firstStep: make(map[int]int), edgeCounts: make(map[edgeKey]int)}
+	for _, path := range paths {
+		for step, id := range path {
+			if first, ok := stats.firstStep[id]; !ok || step < first {
+				stats.firstStep[id] = step
+			}
+			if step > 0 {
+				direction := network.directions[(step-1)%len(network.directions)]
+				stats.edgeCounts[edgeKey{path[step-1], direction}]++
+			}
+		}
+	}
+	return stats
+}
+
+// Writes the network as a DOT digraph, with start nodes (..A) in green and end nodes (..Z) in red.
+// If stats is given, the walks are highlighted with the step each node was first reached.
+func writeDot(w io.Writer, network *Network, stats *pathStats) error {
+	ids := make([]int, network.Size())
+	for id := range ids {
+		ids[id] = id
+	}
+	slices.SortFunc(ids, func(a, b int) int { return strings.Compare(network.Name(a), network.Name(b)) })
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph network {")
+	fmt.Fprintln(bw, "  node [shape=ellipse, style=filled, fillcolor=white];")
+	for _, id := range ids {
+		name := network.Name(id)
+		var attrs []string
+		switch {
+		case strings.HasSuffix(name, "A"):
+			attrs = append(attrs, "fillcolor=palegreen")
+		case strings.HasSuffix(name, "Z"):
+			attrs = append(attrs, "fillcolor=lightcoral")
+		}
+		if stats != nil {
+			if step, ok := stats.firstStep[id]; ok {
+				attrs = append(attrs, fmt.Sprintf("label=%q, penwidth=2", fmt.Sprintf("%v\nstep %v", name, step)))
+			}
+		}
+		fmt.Fprintf(bw, "  %q [%v];\n", name, strings.Join(attrs, ", "))
+	}
+
+	for _, id := range ids {
+		type edge struct {
+			label      string
+			directions []byte
+			to         int
+		}
+		edges := []edge{{"L", []byte{'L'}, network.left[id]}, {"R", []byte{'R'}, network.right[id]}}
+		if network.left[id] == network.right[id] {
+			edges = []edge{{"L/R", []byte{'L', 'R'}, network.left[id]}}
+		}
+		for _, e := range edges {
+			attrs := []string{fmt.Sprintf("label=%q", e.label)}
+			if stats != nil {
+				var count int
+				for _, direction := range e.directions {
+					count += stats.edgeCounts[edgeKey{id, direction}]
+				}
+				if count > 0 {
+					attrs = []string{fmt.Sprintf("label=%q", fmt.Sprintf("%v (x%v)", e.label, count)), "color=red", "penwidth=2"}
+				}
+			}
+			fmt.Fprintf(bw, "  %q -> %q [%v];\n", network.Name(id), network.Name(e.to), strings.Join(attrs, ", "))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func exportDot(file, dotFile string, withPath bool, flags walkFlags) error {
+	network, err := readMapFile(file)
+	if err != nil {
+		return err
+	}
+
+	var stats *pathStats
+	if withPath {
+		walks, err := WalkFromAll(network, flags.starts(network), flags.end())
+		if err != nil {
+			return err
+		}
+		var paths [][]int
+		for _, walk := range walks {
+			paths = append(paths, walk.path)
+		}
+		stats = getPathStats(network, paths...)
+	}
+
+	out, err := os.Create(dotFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := writeDot(out, network, stats); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %v nodes to %v, render it with \"dot -Tsvg %v -o network.svg\"\n", network.Size(), dotFile, dotFile)
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+/********** Jump Tables **********/
+
+// Binary-lifting tables over the states of a walk, where a state is a node and how far into the
+// directions the walk is, encoded as node*len(directions) + directionIndex.
+type JumpTable struct {
+	network       *Network
+	numDirections int
+	// levels[k][state] is the state reached 2^k steps after state, added as longer jumps are needed
+	levels [][]int32
+}
+
+func NewJumpTable(network *Network) (*JumpTable, error) {
+	numDirections := len(network.directions)
+	numStates := network.Size() * numDirections
+	if numStates > math.MaxInt32 {
+		return nil, fmt.Errorf("Network has %v walk states, too many for a jump table", numStates)
+	}
+
+	first := make([]int32, numStates)
+	for node := 0; node < network.Size(); node++ {
+		for step := 0; step < numDirections; step++ {
+			next := network.Next(node, step)*numDirections + (step+1)%numDirections
+			first[node*numDirections+step] = int32(next)
+		}
+	}
+	return &JumpTable{network: network, numDirections: numDirections, levels: [][]int32{first}}, nil
+}
+
+// Adds levels until jumps of 2^level steps are available.
+func (t *JumpTable) grow(level int) {
+	for len(t.levels) <= level {
+		previous := t.levels[len(t.levels)-1]
+		next := make([]int32, len(previous))
+		for state, halfway := range previous {
+			next[state] = previous[halfway]
+		}
+		t.levels = append(t.levels, next)
+	}
+}
+
+// The state reached after the given number of steps from state, in O(log steps) jumps.
+func (t *JumpTable) advance(state, steps int) int {
+	for level := 0; steps > 0; level++ {
+		if steps&1 == 1 {
+			t.grow(level)
+			state = int(t.levels[level][state])
+		}
+		steps >>= 1
+	}
+	return state
+}
+
+// The node reached after the given number of steps of a walk from start.
+func (t *JumpTable) NodeAfter(start, steps int) (int, error) {
+	if steps < 0 {
+		return 0, fmt.Errorf("Cannot walk a negative number of steps (%v)", steps)
+	}
+	return t.advance(start*t.numDirections, steps) / t.numDirections, nil
+}
+
+// The first step at or after offset at which a walk from start is on a node satisfying end.
+// Past offset the walk cycles through at most every state, so it fails if none is found in that many steps.
+func (t *JumpTable) FirstMatchAfter(start, offset int, end EndPredicate) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("Cannot walk a negative number of steps (%v)", offset)
+	}
+	isEnd := t.network.Matches(end)
+	next := t.levels[0]
+	state := t.advance(start*t.numDirections, offset)
+	for step := offset; step-offset < len(next); step++ {
+		if isEnd[state/t.numDirections] {
+			return step, nil
+		}
+		state = int(next[state])
+	}
+	return 0, fmt.Errorf("Walk from %q never reaches an end node after step %v", t.network.Name(start), offset)
+}
+
+// The jump table for the network, built the first time it is needed.
+func (n *Network) Jumps() (*JumpTable, error) {
+	if n.jumps == nil {
+		jumps, err := NewJumpTable(n)
+		if err != nil {
+			return nil, err
+		}
+		n.jumps = jumps
+	}
+	return n.jumps, nil
+}
+
+// The name of the node reached after the given number of steps from start.
+func (n *Network) NodeAfter(start string, steps int) (string, error) {
+	startId, ok := n.Id(start)
+	if !ok {
+		return "", fmt.Errorf("Network has no start node %q", start)
+	}
+	jumps, err := n.Jumps()
+	if err != nil {
+		return "", err
+	}
+	node, err := jumps.NodeAfter(startId, steps)
+	if err != nil {
+		return "", err
+	}
+	return n.Name(node), nil
+}
+
+// The first step at or after offset at which a walk from start is on a node satisfying end.
+func (n *Network) FirstMatchAfter(start string, offset int, end EndPredicate) (int, error) {
+	startId, ok := n.Id(start)
+	if !ok {
+		return 0, fmt.Errorf("Network has no start node %q", start)
+	}
+	jumps, err := n.Jumps()
+	if err != nil {
+		return 0, err
+	}
+	return jumps.FirstMatchAfter(startId, offset, end)
+}
@@ -0,0 +1,120 @@
+package main
+
+import "testing"
+
+func readTestNetwork(t *testing.T) *Network {
+	t.Helper()
+	network, err := readMapFile("input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return network
+}
+
+// Steps one direction at a time from start.
+func naiveNodeAfter(network *Network, start, steps int) int {
+	current := start
+	for step := 0; step < steps; step++ {
+		current = network.Next(current, step)
+	}
+	return current
+}
+
+func TestNodeAfter(t *testing.T) {
+	network := readTestNetwork(t)
+	jumps, err := network.Jumps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []int{0, 1, 2, 3, len(network.directions) - 1, len(network.directions), len(network.directions) + 1, 12643, 54321}
+	for power := 2; power <= 1<<16; power <<= 1 {
+		steps = append(steps, power-1, power, power+1)
+	}
+	starts := append(network.NamesMatching(NameSuffix("A")), "ZZZ")
+	for _, name := range starts {
+		start, _ := network.Id(name)
+		for _, k := range steps {
+			got, err := jumps.NodeAfter(start, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := naiveNodeAfter(network, start, k); got != want {
+				t.Errorf("From %v after %v steps: got %v, expected %v", name, k, network.Name(got), network.Name(want))
+			}
+		}
+	}
+}
+
+func TestNodeAfterLongWalks(t *testing.T) {
+	network := readTestNetwork(t)
+	jumps, err := network.Jumps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Jumping a then b steps must land where jumping a+b does
+	start, _ := network.Id("AAA")
+	for _, split := range [][2]int{{1, 999999999999999}, {1 << 40, 1<<40 + 12345}, {123456789, 987654321012345}} {
+		a, b := split[0], split[1]
+		direct := jumps.advance(start*jumps.numDirections, a+b)
+		twice := jumps.advance(jumps.advance(start*jumps.numDirections, a), b)
+		if direct != twice {
+			t.Errorf("%v+%v steps: got state %v directly and %v in two jumps", a, b, direct, twice)
+		}
+	}
+
+	// AAA returns to ZZZ every 12643 steps
+	for _, k := range []int{12643, 12643 * 1000003, 12643 * 79097201713} {
+		if name, err := network.NodeAfter("AAA", k); err != nil || name != "ZZZ" {
+			t.Errorf("After %v steps got %v, %v, expected ZZZ", k, name, err)
+		}
+	}
+}
+
+func TestFirstMatchAfter(t *testing.T) {
+	network := readTestNetwork(t)
+	walk, err := WalkFrom(network, "AAA", ExactName("ZZZ"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstHit := walk.steps
+
+	tests := []struct {
+		offset, want int
+	}{
+		{0, firstHit},
+		{1, firstHit},
+		{firstHit - 1, firstHit},
+		{firstHit, firstHit},
+		{firstHit + 1, 2 * firstHit},
+		{5*firstHit + 7, 6 * firstHit},
+		{1000000 * firstHit, 1000000 * firstHit},
+	}
+	for _, test := range tests {
+		got, err := network.FirstMatchAfter("AAA", test.offset, ExactName("ZZZ"))
+		if err != nil || got != test.want {
+			t.Errorf("From offset %v: got %v, %v, expected %v", test.offset, got, err, test.want)
+		}
+	}
+}
+
+func TestFirstMatchAfterNeverMatches(t *testing.T) {
+	network := readTestNetwork(t)
+	if _, err := network.FirstMatchAfter("AAA", 0, ExactName("no such node")); err == nil {
+		t.Error("Expected an error for an end node that is never reached")
+	}
+}
+
+func TestNegativeSteps(t *testing.T) {
+	network := readTestNetwork(t)
+	if _, err := network.NodeAfter("AAA", -1); err == nil {
+		t.Error("Expected an error for NodeAfter with -1 steps")
+	}
+	if _, err := network.FirstMatchAfter("AAA", -1, ExactName("ZZZ")); err == nil {
+		t.Error("Expected an error for FirstMatchAfter from offset -1")
+	}
+	if _, err := network.NodeAfter("no such node", 1); err == nil {
+		t.Error("Expected an error for an unknown start node")
+	}
+}
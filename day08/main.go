@@ -4,108 +4,290 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
-	"log"
+	"strings"
+	"unicode"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 type Node struct {
 	name, left, right string
+	// The line the node was read from, if any
+	line int
 }
 
-func followDirectionsToZZZ(directions string, nodeMap map[string]*Node) (int, error) {
-	const start, end = "AAA", "ZZZ"
-	currentNode := nodeMap[start]
-	var totalSteps int
-	for currentNode.name != end {
-		for _, direction := range directions {
-			switch direction {
-			case 'L':
-				currentNode = nodeMap[currentNode.left]
-			case 'R':
-				currentNode = nodeMap[currentNode.right]
-			default:
-				return 0, fmt.Errorf("Got invalid direction: %q", string(direction))
-			}
-			totalSteps++
-			if currentNode.name == end {
-				break
-			}
+/********** Walks **********/
+
+// Decides whether a walk has reached a node it should stop at.
+type EndPredicate func(name string) bool
+
+func ExactName(name string) EndPredicate {
+	return func(n string) bool { return n == name }
+}
+
+func NameSuffix(suffix string) EndPredicate {
+	return func(n string) bool { return strings.HasSuffix(n, suffix) }
+}
+
+func NameSet(names ...string) EndPredicate {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return func(n string) bool { return set[n] }
+}
+
+type Walk struct {
+	start int
+	steps int
+	// The ids of the nodes visited, starting with start
+	path []int
+}
+
+// Follows the directions from start until reaching a node that satisfies end, which may be start itself.
+// Fails if the walk loops forever without reaching an end node.
+func WalkFrom(network *Network, start string, end EndPredicate) (*Walk, error) {
+	startId, ok := network.Id(start)
+	if !ok {
+		return nil, fmt.Errorf("Network has no start node %q", start)
+	}
+	isEnd := network.Matches(end)
+	numDirections := len(network.directions)
+	// Whether each node has been visited at each step into the directions
+	visited := make([]bool, network.Size()*numDirections)
+
+	walk := &Walk{start: startId, path: []int{startId}}
+	for current := startId; !isEnd[current]; walk.path = append(walk.path, current) {
+		state := current*numDirections + walk.steps%numDirections
+		if visited[state] {
+			return nil, fmt.Errorf("Walk from %q loops after %v steps without reaching an end node", start, walk.steps)
 		}
+		visited[state] = true
+		current = network.Next(current, walk.steps)
+		walk.steps++
 	}
-	return totalSteps, nil
+	return walk, nil
 }
 
-func readNodeLine(line string) (*Node, error) {
-	if len(line) != 16 {
-		return nil, fmt.Errorf("Expected line in format \"xxx = (yyy, zzz)\", got %q", line)
+// Walks from each of the starts independently.
+func WalkFromAll(network *Network, starts []string, end EndPredicate) ([]*Walk, error) {
+	var walks []*Walk
+	for _, start := range starts {
+		walk, err := WalkFrom(network, start, end)
+		if err != nil {
+			return nil, err
+		}
+		walks = append(walks, walk)
+	}
+	return walks, nil
+}
+
+// The name of the node the walk stopped at.
+func (w *Walk) End(network *Network) string {
+	return network.Name(w.path[len(w.path)-1])
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
 	}
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
 
-	return &Node{name: line[0:3], left: line[7:10], right: line[12:15]}, nil
+/********** File Functions **********/
+
+// Node names may be any characters except whitespace and punctuation used by the node format.
+func isNameRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune("=(),", r)
+}
+
+// node = name "=" "(" left "," right ")", with any spacing between the parts
+func readNodeLine(line string) (*Node, error) {
+	node := &Node{}
+	name := parser.Span("a node name", isNameRune)
+	grammar := parser.Sequence(
+		parser.Token(parser.Into(name, &node.name)),
+		parser.Token(parser.Literal("=")),
+		parser.Token(parser.Literal("(")),
+		parser.Token(parser.Into(name, &node.left)),
+		parser.Token(parser.Literal(",")),
+		parser.Token(parser.Into(name, &node.right)),
+		parser.Token(parser.Literal(")")),
+	)
+	if _, err := parser.Parse(grammar, line); err != nil {
+		return nil, fmt.Errorf("Error parsing node %q: %w", line, err)
+	}
+	return node, nil
 }
 
-func readMap(scanner *bufio.Scanner) (string, map[string]*Node, error) {
+func readMap(scanner *bufio.Scanner) (*Network, error) {
 	if !scanner.Scan() {
-		return "", nil, fmt.Errorf("File was empty")
+		return nil, fmt.Errorf("File was empty")
+	}
+	directions := strings.TrimSpace(scanner.Text())
+	if len(directions) == 0 {
+		return nil, fmt.Errorf("First line has no directions")
+	}
+	if i := strings.IndexFunc(directions, func(r rune) bool { return r != 'L' && r != 'R' }); i != -1 {
+		return nil, fmt.Errorf("Got invalid direction %q at column %v", directions[i], i+1)
 	}
-	directions := scanner.Text()
 
-	scanner.Scan() // Skip blank line
-	nodeMap := make(map[string]*Node)
-	for scanner.Scan() {
+	nodeLines := make(map[string]int)
+	var nodes []*Node
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
 		node, err := readNodeLine(scanner.Text())
 		if err != nil {
-			return "", nil, err
+			return nil, fmt.Errorf("Line %v: %w", lineNumber, err)
 		}
-		nodeMap[node.name] = node
+		if previous, ok := nodeLines[node.name]; ok {
+			return nil, fmt.Errorf("Line %v: node %q was already defined on line %v", lineNumber, node.name, previous)
+		}
+		node.line = lineNumber
+		nodeLines[node.name] = lineNumber
+		nodes = append(nodes, node)
 	}
-	return directions, nodeMap, nil
+	return NewNetwork(directions, nodes)
 }
 
-func part1(file string) error {
+func readMapFile(file string) (*Network, error) {
 	scanner, err := fileReader.GetFileScanner(file)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return readMap(scanner)
+}
+
+func part1(file string) (int, error) {
+	network, err := readMapFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	walk, err := WalkFrom(network, "AAA", ExactName("ZZZ"))
+	if err != nil {
+		return 0, err
+	}
+
+	return walk.steps, nil
+}
+
+// Assumes, as the puzzle input is built to, that each ghost loops back to its end node in the
+// same number of steps it took to first reach it, so all ghosts meet at the LCM of those steps.
+func part2(file string) (int, error) {
+	network, err := readMapFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	starts := network.NamesMatching(NameSuffix("A"))
+	walks, err := WalkFromAll(network, starts, NameSuffix("Z"))
+	if err != nil {
+		return 0, err
+	}
+
+	totalSteps := 1
+	for _, walk := range walks {
+		totalSteps = lcm(totalSteps, walk.steps)
+	}
+
+	return totalSteps, nil
+}
+
+// The walks chosen by the -from, -from-suffix, -to and -to-suffix flags.
+type walkFlags struct {
+	from, fromSuffix, to, toSuffix string
+}
+
+// The node named by -from, or every node ending in -from-suffix.
+func (f walkFlags) starts(network *Network) []string {
+	if f.fromSuffix != "" {
+		return network.NamesMatching(NameSuffix(f.fromSuffix))
+	}
+	return []string{f.from}
+}
+
+// Stops at any of the nodes in -to, or any node ending in -to-suffix.
+func (f walkFlags) end() EndPredicate {
+	if f.toSuffix != "" {
+		return NameSuffix(f.toSuffix)
 	}
-	directions, nodeMap, err := readMap(scanner)
+	return NameSet(strings.Split(f.to, ",")...)
+}
+
+// Walks from each start until reaching an end node.
+func walkQuery(file string, flags walkFlags) error {
+	network, err := readMapFile(file)
 	if err != nil {
 		return err
 	}
 
-	totalSteps, err := followDirectionsToZZZ(directions, nodeMap)
+	starts := flags.starts(network)
+	walks, err := WalkFromAll(network, starts, flags.end())
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("In part 1, you will reach ZZZ in %v steps\n", totalSteps)
+	for i, walk := range walks {
+		fmt.Printf("Walking from %v reaches %v in %v steps\n", starts[i], walk.End(network), walk.steps)
+	}
 	return nil
 }
 
-func part2(file string) error {
-	fmt.Println("Part 2 is not implemented.")
+// Fast-forwards a walk from -from by -steps steps, then finds the next step on an end node.
+func afterQuery(file string, flags walkFlags, steps int) error {
+	network, err := readMapFile(file)
+	if err != nil {
+		return err
+	}
+
+	node, err := network.NodeAfter(flags.from, steps)
+	if err != nil {
+		return err
+	}
+	step, err := network.FirstMatchAfter(flags.from, steps, flags.end())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Walking from %v is on %v after %v steps, and next reaches an end node at step %v\n", flags.from, node, steps, step)
 	return nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
+	dotFileFlag := flag.String("dot-file", "network.dot", "the file to write the network to with -dot")
+	dotPathFlag := flag.Bool("dot-path", false, "whether to highlight the walks from -from to -to in the -dot output")
+	var walk walkFlags
+	flag.StringVar(&walk.from, "from", "AAA", "the node to start from with -walk, -after or -dot-path")
+	flag.StringVar(&walk.fromSuffix, "from-suffix", "", "if provided, -walk and -dot-path start from every node ending in this instead of -from")
+	flag.StringVar(&walk.to, "to", "ZZZ", "the comma separated nodes to stop at with -walk, -after or -dot-path")
+	flag.StringVar(&walk.toSuffix, "to-suffix", "", "if provided, walks stop at any node ending in this instead of -to")
+	stepsFlag := flag.Int("steps", 1000000000000000, "the number of steps to fast-forward with -after")
 
-	return
+	runner.Day{
+		Number: 8,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file) }},
+		},
+		Tools: []runner.Tool{
+			{Name: "after", Usage: "whether to print the node reached from -from after -steps steps", Run: func(file string) error {
+				return afterQuery(file, walk, *stepsFlag)
+			}},
+			{Name: "dot", Usage: "whether to export the network as a Graphviz DOT file", Run: func(file string) error {
+				return exportDot(file, *dotFileFlag, *dotPathFlag, walk)
+			}},
+			{Name: "walk", Usage: "whether to print the number of steps from -from to -to", Run: func(file string) error {
+				return walkQuery(file, walk)
+			}},
+		},
+	}.Main()
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+/********** Network **********/
+
+// The network with node names interned as dense ids, so each step is an array lookup.
+type Network struct {
+	directions string
+	// Node names by id, and ids by name
+	names []string
+	ids   map[string]int
+	// The node reached by going left or right from each node
+	left, right []int
+	// The node reached from each node after following every direction once
+	pass []int
+	// Built by Jumps when first needed
+	jumps *JumpTable
+}
+
+// Interns the nodes in the order given. Fails if a node refers to one that isn't among them.
+func NewNetwork(directions string, nodes []*Node) (*Network, error) {
+	network := &Network{
+		directions: directions,
+		names:      make([]string, len(nodes)),
+		ids:        make(map[string]int, len(nodes)),
+		left:       make([]int, len(nodes)),
+		right:      make([]int, len(nodes)),
+	}
+	for id, node := range nodes {
+		network.names[id] = node.name
+		network.ids[node.name] = id
+	}
+	for id, node := range nodes {
+		for _, name := range []string{node.left, node.right} {
+			if _, ok := network.ids[name]; ok {
+				continue
+			}
+			if node.line > 0 {
+				return nil, fmt.Errorf("Line %v: node %q refers to undefined node %q", node.line, node.name, name)
+			}
+			return nil, fmt.Errorf("Node %q refers to undefined node %q", node.name, name)
+		}
+		network.left[id], network.right[id] = network.ids[node.left], network.ids[node.right]
+	}
+
+	network.pass = make([]int, len(nodes))
+	for id := range nodes {
+		current := id
+		for step := range directions {
+			current = network.Next(current, step)
+		}
+		network.pass[id] = current
+	}
+	return network, nil
+}
+
+func (n *Network) Size() int {
+	return len(n.names)
+}
+
+func (n *Network) Name(id int) string {
+	return n.names[id]
+}
+
+func (n *Network) Id(name string) (int, bool) {
+	id, ok := n.ids[name]
+	return id, ok
+}
+
+// The node reached from node on the given step of a walk, counting from 0.
+func (n *Network) Next(node, step int) int {
+	if n.directions[step%len(n.directions)] == 'L' {
+		return n.left[node]
+	}
+	return n.right[node]
+}
+
+// The node reached from node after following every direction once.
+func (n *Network) AfterPass(node int) int {
+	return n.pass[node]
+}
+
+// Whether each node satisfies the predicate, by id.
+func (n *Network) Matches(pred EndPredicate) []bool {
+	matches := make([]bool, len(n.names))
+	for id, name := range n.names {
+		matches[id] = pred(name)
+	}
+	return matches
+}
+
+// The names of all nodes satisfying the predicate, in sorted order.
+func (n *Network) NamesMatching(pred EndPredicate) []string {
+	var names []string
+	for _, name := range n.names {
+		if pred(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestAfterPass(t *testing.T) {
+	network := readTestNetwork(t)
+	for node := 0; node < network.Size(); node++ {
+		if got, want := network.AfterPass(node), naiveNodeAfter(network, node, len(network.directions)); got != want {
+			t.Errorf("After a pass from %v: got %v, expected %v", network.Name(node), network.Name(got), network.Name(want))
+		}
+	}
+}
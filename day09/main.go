@@ -3,11 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
-	"strconv"
-	"strings"
 
 	fileReader "github.com/scottkerkvliet/advent-of-code-2023/utils/file-reader"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/parser"
+	"github.com/scottkerkvliet/advent-of-code-2023/utils/runner"
 )
 
 type History []int
@@ -45,68 +44,51 @@ func (h History) PredictPreviousValue() int {
 }
 
 func readHistoryLine(line string) (History, error) {
-	valueParts := strings.Split(line, " ")
-	var h History
-	for _, valuePart := range valueParts {
-		value, err := strconv.Atoi(valuePart)
-		if err != nil {
-			return nil, err
-		}
-		h = append(h, value)
+	values, err := parser.ParseInts(line)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing history %q: %w", line, err)
 	}
-	return h, nil
+	return History(values), nil
 }
 
-func part1(file string) error {
-	histories, err := fileReader.ReadFileByLine(file, readHistoryLine)
+func part1(file string, workers int) (int, error) {
+	sum, err := fileReader.ReduceFileByLineParallel(file, workers, func(_ int, line string) (int, error) {
+		h, err := readHistoryLine(line)
+		if err != nil {
+			return 0, err
+		}
+		return h.PredictNextValue(), nil
+	}, 0, fileReader.Sum[int])
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var sum int
-	for _, h := range histories {
-		sum += h.PredictNextValue()
-	}
-
-	fmt.Printf("The sum of the next values is %v\n", sum)
-	return nil
+	return sum, nil
 }
 
-func part2(file string) error {
-	histories, err := fileReader.ReadFileByLine(file, readHistoryLine)
+func part2(file string, workers int) (int, error) {
+	sum, err := fileReader.ReduceFileByLineParallel(file, workers, func(_ int, line string) (int, error) {
+		h, err := readHistoryLine(line)
+		if err != nil {
+			return 0, err
+		}
+		return h.PredictPreviousValue(), nil
+	}, 0, fileReader.Sum[int])
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var sum int
-	for _, h := range histories {
-		sum += h.PredictPreviousValue()
-	}
-
-	fmt.Printf("The sum of the previous values is %v\n", sum)
-	return nil
+	return sum, nil
 }
 
 func main() {
-	inputFile := flag.String("file", "input.txt", "the input file to execute")
-	part1Flag := flag.Bool("1", false, "whether to execute puzzle 1")
-	part2Flag := flag.Bool("2", false, "whether to execute puzzle 2")
-	flag.Parse()
-	if !(*part1Flag || *part2Flag) {
-		fmt.Println("Nothing to do, specify a puzzle to solve")
-		return
-	}
-
-	if *part1Flag {
-		if err := part1(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
-	if *part2Flag {
-		if err := part2(*inputFile); err != nil {
-			log.Fatal(err)
-		}
-	}
+	workersFlag := flag.Int("workers", 0, "the number of lines to solve at once, defaults to one per CPU")
 
-	return
+	runner.Day{
+		Number: 9,
+		Parts: []runner.Part{
+			{Name: "1", Run: func(file string) (any, error) { return part1(file, *workersFlag) }},
+			{Name: "2", Run: func(file string) (any, error) { return part2(file, *workersFlag) }},
+		},
+	}.Main()
 }
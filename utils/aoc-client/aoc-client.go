@@ -0,0 +1,228 @@
+package aocClient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/***** Types *****/
+
+// The subset of *http.Client used by Client, so that requests can be faked.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Client struct {
+	http    HTTPClient
+	baseURL string
+	session string
+	year    int
+	// The minimum time between requests made by this client
+	minInterval time.Duration
+
+	mu          sync.Mutex
+	lastRequest time.Time
+	// If set, the time of the last request is shared through this file, so separate processes
+	// using the same file are throttled together
+	lastRequestFile string
+}
+
+var ErrRateLimited = errors.New("rate limited by server")
+
+const (
+	DefaultBaseURL     = "https://adventofcode.com"
+	DefaultMinInterval = 5 * time.Second
+	userAgent          = "github.com/scottkerkvliet/advent-of-code-2023"
+)
+
+/***** Methods *****/
+
+func NewClient(httpClient HTTPClient, baseURL, session string, year int) *Client {
+	return &Client{
+		http:        httpClient,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		session:     session,
+		year:        year,
+		minInterval: DefaultMinInterval,
+	}
+}
+
+func (c *Client) SetMinInterval(interval time.Duration) {
+	c.minInterval = interval
+}
+
+func (c *Client) SetLastRequestFile(path string) {
+	c.lastRequestFile = path
+}
+
+func readLastRequest(path string) (time.Time, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	last, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(contents)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Error parsing last request time in %q: %w", path, err)
+	}
+	return last, nil
+}
+
+func writeLastRequest(path string, last time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(last.Format(time.RFC3339Nano)+"\n"), 0644)
+}
+
+// Waits until minInterval has passed since the last request, including the one recorded in the
+// last request file if there is one, then records this request.
+func (c *Client) throttle() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := c.lastRequest
+	if c.lastRequestFile != "" {
+		saved, err := readLastRequest(c.lastRequestFile)
+		if err != nil {
+			return err
+		}
+		if saved.After(last) {
+			last = saved
+		}
+	}
+	// Never wait longer than the interval, in case the saved time is in the future
+	if wait := min(c.minInterval, c.minInterval-time.Since(last)); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	c.lastRequest = time.Now()
+	if c.lastRequestFile != "" {
+		return writeLastRequest(c.lastRequestFile, c.lastRequest)
+	}
+	return nil
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if c.session == "" {
+		return nil, fmt.Errorf("No session token set")
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.session})
+
+	if err := c.throttle(); err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if retry := resp.Header.Get("Retry-After"); retry != "" {
+			return nil, fmt.Errorf("%w, retry after %v seconds", ErrRateLimited, retry)
+		}
+		return nil, ErrRateLimited
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%v %v returned %v: %v", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func (c *Client) dayURL(day int) string {
+	return fmt.Sprintf("%v/%v/day/%v", c.baseURL, c.year, day)
+}
+
+// Downloads the puzzle input for the day.
+func (c *Client) FetchInput(day int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.dayURL(day)+"/input", nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+/***** Submissions *****/
+
+type Verdict string
+
+const (
+	Correct       Verdict = "correct"
+	TooHigh       Verdict = "too high"
+	TooLow        Verdict = "too low"
+	Wrong         Verdict = "wrong"
+	RateLimited   Verdict = "rate limited"
+	AlreadySolved Verdict = "already solved"
+	Unknown       Verdict = "unknown"
+)
+
+type SubmitResult struct {
+	Verdict Verdict
+	// The text of the server's response
+	Message string
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Reads the verdict from the HTML page returned after submitting an answer.
+func ParseSubmitResponse(body string) SubmitResult {
+	message := body
+	if match := articlePattern.FindStringSubmatch(body); match != nil {
+		message = match[1]
+	}
+	message = strings.Join(strings.Fields(tagPattern.ReplaceAllString(message, " ")), " ")
+
+	result := SubmitResult{Verdict: Unknown, Message: message}
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		result.Verdict = Correct
+	case strings.Contains(message, "your answer is too high"):
+		result.Verdict = TooHigh
+	case strings.Contains(message, "your answer is too low"):
+		result.Verdict = TooLow
+	case strings.Contains(message, "That's not the right answer"):
+		result.Verdict = Wrong
+	case strings.Contains(message, "You gave an answer too recently"):
+		result.Verdict = RateLimited
+	case strings.Contains(message, "Did you already complete it"):
+		result.Verdict = AlreadySolved
+	}
+	return result
+}
+
+// Posts the answer for a part of the day.
+func (c *Client) Submit(day, part int, answer string) (SubmitResult, error) {
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, c.dayURL(day)+"/answer", strings.NewReader(form.Encode()))
+	if err != nil {
+		return SubmitResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := c.do(req)
+	if err != nil {
+		return SubmitResult{}, err
+	}
+	return ParseSubmitResponse(string(body)), nil
+}
@@ -0,0 +1,180 @@
+package aocClient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Starts a local server with the handler and a client for it that doesn't throttle.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := NewClient(server.Client(), server.URL, "test-session", 2023)
+	client.SetMinInterval(0)
+	return client
+}
+
+func TestFetchInput(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2023/day/5/input" {
+			t.Errorf("Got request %v %v", r.Method, r.URL.Path)
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "test-session" {
+			t.Errorf("Got session cookie %v, %v", cookie, err)
+		}
+		if agent := r.UserAgent(); agent != userAgent {
+			t.Errorf("Got User-Agent %q", agent)
+		}
+		w.Write([]byte("1 2 3\n"))
+	})
+
+	input, err := client.FetchInput(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(input) != "1 2 3\n" {
+		t.Errorf("Got input %q", input)
+	}
+}
+
+func TestFetchInputWithoutSession(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request was made without a session")
+	})
+	client.session = ""
+
+	if _, err := client.FetchInput(5); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestFetchInputNotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Please don't repeatedly request this endpoint before it unlocks!", http.StatusNotFound)
+	})
+
+	_, err := client.FetchInput(25)
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "before it unlocks") {
+		t.Errorf("Got error %v", err)
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Errorf("A 404 should not count as rate limited")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	tests := []struct {
+		retryAfter string
+		want       string
+	}{
+		{"", "rate limited by server"},
+		{"30", "rate limited by server, retry after 30 seconds"},
+	}
+	for _, test := range tests {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if test.retryAfter != "" {
+				w.Header().Set("Retry-After", test.retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		_, err := client.FetchInput(1)
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Retry-After %q: got error %v, expected ErrRateLimited", test.retryAfter, err)
+		} else if err.Error() != test.want {
+			t.Errorf("Retry-After %q: got error %q, expected %q", test.retryAfter, err, test.want)
+		}
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2023/day/8/answer" {
+			t.Errorf("Got request %v %v", r.Method, r.URL.Path)
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/x-www-form-urlencoded" {
+			t.Errorf("Got Content-Type %q", contentType)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if level, answer := r.PostForm.Get("level"), r.PostForm.Get("answer"); level != "2" || answer != "a b&c" {
+			t.Errorf("Got level %q and answer %q", level, answer)
+		}
+		w.Write([]byte("<html><main><article><p>That's the right answer! You are <em>one gold star</em> closer.</p></article></main></html>"))
+	})
+
+	result, err := client.Submit(8, 2, "a b&c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != Correct {
+		t.Errorf("Got verdict %q", result.Verdict)
+	}
+	if result.Message != "That's the right answer! You are one gold star closer." {
+		t.Errorf("Got message %q", result.Message)
+	}
+}
+
+func TestParseSubmitResponse(t *testing.T) {
+	tests := []struct {
+		body string
+		want Verdict
+	}{
+		{"<article><p>That's the right answer! You are one gold star closer.</p></article>", Correct},
+		{"<article><p>That's not the right answer; your answer is too high. Please wait one minute.</p></article>", TooHigh},
+		{"<article><p>That's not the right answer; your answer is too low.</p></article>", TooLow},
+		{"<article><p>That's not the right answer. If you're stuck, make sure you're using the full input.</p></article>", Wrong},
+		{"<article><p>You gave an answer too recently; you have to wait after submitting an answer.</p></article>", RateLimited},
+		{"<article><p>You don't seem to be solving the right level. Did you already complete it?</p></article>", AlreadySolved},
+		{"<html><body>Something else entirely</body></html>", Unknown},
+	}
+	for _, test := range tests {
+		if got := ParseSubmitResponse(test.body); got.Verdict != test.want {
+			t.Errorf("Got verdict %q for %q, expected %q", got.Verdict, test.body, test.want)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	client.SetMinInterval(100 * time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.FetchInput(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request doesn't wait, the next two each wait for the interval
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Three requests took %v, expected at least 200ms", elapsed)
+	}
+}
+
+func TestThrottleSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aoc", "last-request")
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	first, second := newTestClient(t, handler), newTestClient(t, handler)
+	for _, client := range []*Client{first, second} {
+		client.SetMinInterval(200 * time.Millisecond)
+		client.SetLastRequestFile(path)
+	}
+
+	if _, err := first.FetchInput(1); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := second.FetchInput(1); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Second client waited %v, expected it to wait for the first client's request", elapsed)
+	}
+}
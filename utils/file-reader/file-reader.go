@@ -2,6 +2,7 @@ package fileReader
 
 import (
 	"bufio"
+	"errors"
 	"os"
 )
 
@@ -9,6 +10,12 @@ import (
 
 type LineReader[T any] func(string) (T, error)
 
+// NumberedLineReader is a LineReader that is also given the 1-based number of the line.
+type NumberedLineReader[T any] func(int, string) (T, error)
+
+// ErrSkipLine can be returned by a line reader to leave the line out of the results.
+var ErrSkipLine = errors.New("skip line")
+
 /***** Methods *****/
 
 func GetFileScanner(path string) (*bufio.Scanner, error) {
@@ -20,21 +27,38 @@ func GetFileScanner(path string) (*bufio.Scanner, error) {
 	return bufio.NewScanner(file), nil
 }
 
+// Adapts a LineReader to be used where a NumberedLineReader is expected.
+func IgnoreLineNumber[K any](lr LineReader[K]) NumberedLineReader[K] {
+	return func(_ int, line string) (K, error) {
+		return lr(line)
+	}
+}
+
 func ReadFileByLine[K any](path string, lr LineReader[K]) ([]K, error) {
+	return ReadFileByNumberedLine(path, IgnoreLineNumber(lr))
+}
+
+func ReadFileByNumberedLine[K any](path string, lr NumberedLineReader[K]) ([]K, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
 	var values []K
+	var lineNumber int
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		value, err := lr(scanner.Text())
+		lineNumber++
+		value, err := lr(lineNumber, scanner.Text())
+		if errors.Is(err, ErrSkipLine) {
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
 		values = append(values, value)
 	}
 
-	return values, nil
+	return values, scanner.Err()
 }
@@ -0,0 +1,104 @@
+package fileReader
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+/***** Parallel Methods *****/
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Like ReadFileByNumberedLine, but the lines are handed out to the given number of workers,
+// or one per CPU if workers is not positive. Values are returned in line order, and if any
+// lines fail the error from the earliest of them is returned.
+func ReadFileByLineParallel[K any](path string, workers int, lr NumberedLineReader[K]) ([]K, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	values := make([]K, len(lines))
+	errs := make([]error, len(lines))
+	// Lines after the earliest failure so far don't need to be read
+	var firstFailure atomic.Int64
+	firstFailure.Store(int64(len(lines)))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if int64(i) > firstFailure.Load() {
+					continue
+				}
+				values[i], errs[i] = lr(i+1, lines[i])
+				if errs[i] != nil && !errors.Is(errs[i], ErrSkipLine) {
+					for failure := firstFailure.Load(); int64(i) < failure; failure = firstFailure.Load() {
+						if firstFailure.CompareAndSwap(failure, int64(i)) {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+	for i := range lines {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	var results []K
+	for i, value := range values {
+		if errors.Is(errs[i], ErrSkipLine) {
+			continue
+		}
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+// Reads the file with ReadFileByLineParallel, then folds the values into a result in line order.
+func ReduceFileByLineParallel[K, R any](path string, workers int, lr NumberedLineReader[K], initial R, combine func(R, K) R) (R, error) {
+	values, err := ReadFileByLineParallel(path, workers, lr)
+	if err != nil {
+		return initial, err
+	}
+
+	result := initial
+	for _, value := range values {
+		result = combine(result, value)
+	}
+	return result, nil
+}
+
+// A combiner for ReduceFileByLineParallel that adds up the values.
+func Sum[K ~int | ~int64](total, value K) K {
+	return total + value
+}
@@ -0,0 +1,137 @@
+package fileReader
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const numTestLines = 1000
+
+// Writes a file whose lines are "skip" when the line number is a multiple of 7, "fail" for
+// the given line numbers, and the line number otherwise.
+func writeTestFile(t *testing.T, failing ...int) string {
+	t.Helper()
+	var lines []string
+	for n := 1; n <= numTestLines; n++ {
+		switch {
+		case n%7 == 0:
+			lines = append(lines, "skip")
+		case contains(failing, n):
+			lines = append(lines, "fail")
+		default:
+			lines = append(lines, strconv.Itoa(n))
+		}
+	}
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads the test file lines. Failing lines take a random time, so failures are found in
+// varying orders between runs.
+func testLineReader() NumberedLineReader[int] {
+	return func(n int, line string) (int, error) {
+		switch line {
+		case "skip":
+			return 0, ErrSkipLine
+		case "fail":
+			time.Sleep(time.Duration(rand.Intn(300)) * time.Microsecond)
+			return 0, fmt.Errorf("line %v failed", n)
+		}
+		value, err := strconv.Atoi(line)
+		if err == nil && value != n {
+			err = fmt.Errorf("line %v was given line number %v", value, n)
+		}
+		return value, err
+	}
+}
+
+func TestReadFileByLineParallelOrder(t *testing.T) {
+	path := writeTestFile(t)
+	var want []int
+	for n := 1; n <= numTestLines; n++ {
+		if n%7 != 0 {
+			want = append(want, n)
+		}
+	}
+
+	for _, workers := range []int{0, 1, 2, 3, 8, 64} {
+		for run := 0; run < 20; run++ {
+			values, err := ReadFileByLineParallel(path, workers, testLineReader())
+			if err != nil {
+				t.Fatalf("%v workers: %v", workers, err)
+			}
+			if len(values) != len(want) {
+				t.Fatalf("%v workers: got %v values, expected %v", workers, len(values), len(want))
+			}
+			for i := range want {
+				if values[i] != want[i] {
+					t.Fatalf("%v workers: value %v is %v, expected %v", workers, i, values[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestReadFileByLineParallelEarliestFailure(t *testing.T) {
+	tests := [][]int{
+		{500},
+		{3, 998},
+		{250, 251, 600, 999},
+		{998, 999, 1000},
+		{1, 2, 500, 1000},
+	}
+	for _, failing := range tests {
+		path := writeTestFile(t, failing...)
+		want := fmt.Sprintf("line %v failed", failing[0])
+		for _, workers := range []int{1, 2, 4, 16} {
+			for run := 0; run < 25; run++ {
+				values, err := ReadFileByLineParallel(path, workers, testLineReader())
+				if err == nil || err.Error() != want {
+					t.Fatalf("Failing lines %v with %v workers: got error %v, expected %q", failing, workers, err, want)
+				}
+				if values != nil {
+					t.Fatalf("Failing lines %v with %v workers: got values along with the error", failing, workers)
+				}
+			}
+		}
+	}
+}
+
+func TestReduceFileByLineParallel(t *testing.T) {
+	path := writeTestFile(t)
+	var want int
+	for n := 1; n <= numTestLines; n++ {
+		if n%7 != 0 {
+			want += n
+		}
+	}
+
+	sum, err := ReduceFileByLineParallel(path, 4, testLineReader(), 0, Sum[int])
+	if err != nil || sum != want {
+		t.Errorf("Got sum %v, %v, expected %v", sum, err, want)
+	}
+
+	path = writeTestFile(t, 10, 20)
+	sum, err = ReduceFileByLineParallel(path, 4, testLineReader(), 100, Sum[int])
+	if err == nil || err.Error() != "line 10 failed" || sum != 100 {
+		t.Errorf("Got sum %v, %v, expected the initial value and line 10's error", sum, err)
+	}
+}
@@ -0,0 +1,284 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/***** Types *****/
+
+// The text being parsed and the position reached so far.
+type Input struct {
+	text string
+	pos  int
+}
+
+// A parse failure at a 1-based column of the text.
+type Error struct {
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %v: %v", e.Column, e.Message)
+}
+
+// Consumes a prefix of the input and returns its value. On failure the position is undefined.
+type Parser[T any] func(in *Input) (T, error)
+
+// Any parser, run only for its effect on the input. Used by Sequence.
+type Step interface {
+	step(in *Input) error
+}
+
+func (p Parser[T]) step(in *Input) error {
+	_, err := p(in)
+	return err
+}
+
+/***** Input Methods *****/
+
+func (in *Input) rest() string {
+	return in.text[in.pos:]
+}
+
+func (in *Input) errorAt(pos int, format string, args ...any) error {
+	return &Error{Column: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// An error for the current position, describing what was expected and what is there instead.
+func (in *Input) expected(what string) error {
+	found := "end of line"
+	if rest := in.rest(); len(rest) > 10 {
+		found = strconv.Quote(rest[:10] + "...")
+	} else if len(rest) > 0 {
+		found = strconv.Quote(rest)
+	}
+	return in.errorAt(in.pos, "expected %v, found %v", what, found)
+}
+
+// Consumes the longest prefix of runes matching the predicate.
+func (in *Input) takeWhile(pred func(rune) bool) string {
+	start := in.pos
+	for in.pos < len(in.text) {
+		r, size := utf8.DecodeRuneInString(in.rest())
+		if !pred(r) {
+			break
+		}
+		in.pos += size
+	}
+	return in.text[start:in.pos]
+}
+
+/***** Parsing *****/
+
+// Parses the whole text with p, failing if anything is left over.
+func Parse[T any](p Parser[T], text string) (T, error) {
+	in := &Input{text: text}
+	value, err := p(in)
+	if err != nil {
+		return value, err
+	}
+	if in.pos != len(in.text) {
+		return value, in.expected("end of line")
+	}
+	return value, nil
+}
+
+/***** Basic Parsers *****/
+
+// Matches exactly the given text.
+func Literal(lit string) Parser[string] {
+	return func(in *Input) (string, error) {
+		if !strings.HasPrefix(in.rest(), lit) {
+			return "", in.expected(strconv.Quote(lit))
+		}
+		in.pos += len(lit)
+		return lit, nil
+	}
+}
+
+// Matches a decimal integer with an optional sign.
+func Int() Parser[int] {
+	return func(in *Input) (int, error) {
+		start := in.pos
+		if strings.HasPrefix(in.rest(), "-") || strings.HasPrefix(in.rest(), "+") {
+			in.pos++
+		}
+		if len(in.takeWhile(isDigit)) == 0 {
+			in.pos = start
+			return 0, in.expected("a number")
+		}
+		value, err := strconv.Atoi(in.text[start:in.pos])
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, in.errorAt(start, "number %v is out of range", in.text[start:in.pos])
+		}
+		return value, err
+	}
+}
+
+// Matches one or more integers separated by spaces or tabs, along with any spaces around them.
+// The list ends at the first thing after a space that doesn't start a number.
+func Ints() Parser[[]int] {
+	number := Int()
+	return func(in *Input) ([]int, error) {
+		var values []int
+		in.takeWhile(isSpace)
+		for {
+			value, err := number(in)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+
+			if len(in.takeWhile(isSpace)) == 0 || !startsNumber(in.rest()) {
+				return values, nil
+			}
+		}
+	}
+}
+
+// Parses a line made up only of integers separated by spaces or tabs.
+func ParseInts(text string) ([]int, error) {
+	return Parse(Ints(), text)
+}
+
+// Matches one or more letters, digits or underscores.
+func Word() Parser[string] {
+	return func(in *Input) (string, error) {
+		word := in.takeWhile(isWordRune)
+		if len(word) == 0 {
+			return "", in.expected("a word")
+		}
+		return word, nil
+	}
+}
+
+// Matches one or more runes accepted by pred, described by what in errors.
+func Span(what string, pred func(rune) bool) Parser[string] {
+	return func(in *Input) (string, error) {
+		span := in.takeWhile(pred)
+		if len(span) == 0 {
+			return "", in.expected(what)
+		}
+		return span, nil
+	}
+}
+
+// Matches zero or more spaces or tabs.
+func Spaces() Parser[string] {
+	return func(in *Input) (string, error) {
+		return in.takeWhile(isSpace), nil
+	}
+}
+
+// Matches one or more spaces or tabs.
+func RequiredSpaces() Parser[string] {
+	return func(in *Input) (string, error) {
+		spaces := in.takeWhile(isSpace)
+		if len(spaces) == 0 {
+			return "", in.expected("a space")
+		}
+		return spaces, nil
+	}
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// Whether the text begins with a digit, or a sign followed by a digit.
+func startsNumber(text string) bool {
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+	return len(text) > 0 && isDigit(rune(text[0]))
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+/***** Combinators *****/
+
+// Runs each step in order, failing at the first step that fails.
+func Sequence(steps ...Step) Parser[struct{}] {
+	return func(in *Input) (struct{}, error) {
+		for _, s := range steps {
+			if err := s.step(in); err != nil {
+				return struct{}{}, err
+			}
+		}
+		return struct{}{}, nil
+	}
+}
+
+// Stores the value of p in dst when p succeeds.
+func Into[T any](p Parser[T], dst *T) Parser[T] {
+	return func(in *Input) (T, error) {
+		value, err := p(in)
+		if err == nil {
+			*dst = value
+		}
+		return value, err
+	}
+}
+
+// Converts the value of p with f. Errors from f are reported at the start of p's match.
+func Map[A, B any](p Parser[A], f func(A) (B, error)) Parser[B] {
+	return func(in *Input) (B, error) {
+		start := in.pos
+		value, err := p(in)
+		if err != nil {
+			var zero B
+			return zero, err
+		}
+		result, err := f(value)
+		if err != nil {
+			var parseErr *Error
+			if errors.As(err, &parseErr) {
+				return result, err
+			}
+			return result, in.errorAt(start, "%v", err)
+		}
+		return result, nil
+	}
+}
+
+// Matches p surrounded by optional spaces.
+func Token[T any](p Parser[T]) Parser[T] {
+	return func(in *Input) (T, error) {
+		in.takeWhile(isSpace)
+		value, err := p(in)
+		in.takeWhile(isSpace)
+		return value, err
+	}
+}
+
+// Matches one or more of p, separated by sep.
+func SepBy[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
+	return func(in *Input) ([]T, error) {
+		var values []T
+		for {
+			value, err := p(in)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+
+			beforeSep := in.pos
+			if _, err := sep(in); err != nil {
+				in.pos = beforeSep
+				return values, nil
+			}
+		}
+	}
+}
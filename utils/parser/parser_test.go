@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Checks that err is a parse error at the given column with the given message.
+func checkError(t *testing.T, text string, err error, column int, message string) {
+	t.Helper()
+	var parseErr *Error
+	if !errors.As(err, &parseErr) {
+		t.Errorf("%q: got error %v, expected a parse error", text, err)
+		return
+	}
+	if parseErr.Column != column || parseErr.Message != message {
+		t.Errorf("%q: got error %q, expected column %v: %v", text, err, column, message)
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int
+		column  int
+		message string
+	}{
+		{text: "42", want: 42},
+		{text: "-42", want: -42},
+		{text: "+42", want: 42},
+		{text: "0", want: 0},
+		{text: "9223372036854775807", want: 9223372036854775807},
+		{text: "-9223372036854775808", want: -9223372036854775808},
+		{text: "-", column: 1, message: `expected a number, found "-"`},
+		{text: "+x", column: 1, message: `expected a number, found "+x"`},
+		{text: "", column: 1, message: "expected a number, found end of line"},
+		{text: "9223372036854775808", column: 1, message: "number 9223372036854775808 is out of range"},
+		{text: "-99999999999999999999", column: 1, message: "number -99999999999999999999 is out of range"},
+	}
+	for _, test := range tests {
+		got, err := Parse(Int(), test.text)
+		if test.message != "" {
+			checkError(t, test.text, err, test.column, test.message)
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%q: got %v, %v, expected %v", test.text, got, err, test.want)
+		}
+	}
+}
+
+func TestInts(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []int
+		column  int
+		message string
+	}{
+		{text: "1 2 3", want: []int{1, 2, 3}},
+		{text: "1\t2\t\t3", want: []int{1, 2, 3}},
+		{text: "  1   -2    +3", want: []int{1, -2, 3}},
+		{text: "1 2 3   ", want: []int{1, 2, 3}},
+		{text: " \t7 \t", want: []int{7}},
+		{text: "", column: 1, message: "expected a number, found end of line"},
+		{text: "   ", column: 4, message: "expected a number, found end of line"},
+		{text: "1 2 x", column: 5, message: `expected end of line, found "x"`},
+		{text: "1 2x", column: 4, message: `expected end of line, found "x"`},
+		{text: "1 99999999999999999999", column: 3, message: "number 99999999999999999999 is out of range"},
+	}
+	for _, test := range tests {
+		got, err := ParseInts(test.text)
+		if test.message != "" {
+			checkError(t, test.text, err, test.column, test.message)
+			continue
+		}
+		if err != nil || !slices.Equal(got, test.want) {
+			t.Errorf("%q: got %v, %v, expected %v", test.text, got, err, test.want)
+		}
+	}
+}
+
+func TestIntsStopsBeforeOtherText(t *testing.T) {
+	var first, second []int
+	grammar := Sequence(Into(Ints(), &first), Literal("|"), Into(Ints(), &second))
+	if _, err := Parse(grammar, " 41 48 | 83  86 6 "); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(first, []int{41, 48}) || !slices.Equal(second, []int{83, 86, 6}) {
+		t.Errorf("Got %v and %v", first, second)
+	}
+}
+
+func TestSepBy(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []int
+		column  int
+		message string
+	}{
+		{text: "1", want: []int{1}},
+		{text: "1,2,3", want: []int{1, 2, 3}},
+		{text: "1,2,", column: 5, message: "expected a number, found end of line"},
+		{text: "1,,2", column: 3, message: `expected a number, found ",2"`},
+		{text: ",1", column: 1, message: `expected a number, found ",1"`},
+		{text: "1;2", column: 2, message: `expected end of line, found ";2"`},
+	}
+	for _, test := range tests {
+		got, err := Parse(SepBy(Int(), Literal(",")), test.text)
+		if test.message != "" {
+			checkError(t, test.text, err, test.column, test.message)
+			continue
+		}
+		if err != nil || !slices.Equal(got, test.want) {
+			t.Errorf("%q: got %v, %v, expected %v", test.text, got, err, test.want)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		text    string
+		column  int
+		message string
+	}{
+		{text: "a=b"},
+		{text: "  a \t=  b  "},
+		{text: "a = = b", column: 5, message: `expected a word, found "= b"`},
+		{text: " a b", column: 4, message: `expected "=", found "b"`},
+	}
+	for _, test := range tests {
+		var left, right string
+		grammar := Sequence(Token(Into(Word(), &left)), Token(Literal("=")), Token(Into(Word(), &right)))
+		_, err := Parse(grammar, test.text)
+		if test.message != "" {
+			checkError(t, test.text, err, test.column, test.message)
+			continue
+		}
+		if err != nil || left != "a" || right != "b" {
+			t.Errorf("%q: got %q, %q, %v", test.text, left, right, err)
+		}
+	}
+}
+
+func TestParseLeftoverColumn(t *testing.T) {
+	tests := []struct {
+		text    string
+		column  int
+		message string
+	}{
+		{"12ab", 3, `expected end of line, found "ab"`},
+		{"12 ", 3, `expected end of line, found " "`},
+		{"7 and a long tail of text", 2, `expected end of line, found " and a lon..."`},
+		// Columns count bytes, as the input is read
+		{"1é", 2, `expected end of line, found "é"`},
+	}
+	for _, test := range tests {
+		_, err := Parse(Int(), test.text)
+		checkError(t, test.text, err, test.column, test.message)
+	}
+}
+
+func TestMapReportsErrorsAtStart(t *testing.T) {
+	even := Map(Int(), func(n int) (int, error) {
+		if n%2 != 0 {
+			return 0, errors.New("odd number")
+		}
+		return n, nil
+	})
+	_, err := Parse(Sequence(Literal("n="), Spaces(), even), "n=  13")
+	checkError(t, "n=  13", err, 5, "odd number")
+}
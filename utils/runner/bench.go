@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+)
+
+/***** Benchmarks *****/
+
+type BenchOptions struct {
+	baseline     string
+	saveBaseline bool
+	threshold    float64
+}
+
+// The saved results of a part's benchmark.
+type BenchResult struct {
+	NsPerOp     int64 `json:"nsPerOp"`
+	AllocsPerOp int64 `json:"allocsPerOp"`
+	BytesPerOp  int64 `json:"bytesPerOp"`
+}
+
+func readBaseline(path string) (map[string]BenchResult, error) {
+	baseline := make(map[string]BenchResult)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, &baseline); err != nil {
+		return nil, fmt.Errorf("Error parsing baseline %q: %w", path, err)
+	}
+	return baseline, nil
+}
+
+func writeBaseline(path string, baseline map[string]BenchResult) error {
+	contents, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// Runs the part as a benchmark, discarding its answers.
+func benchmarkPart(file string, part Part) (testing.BenchmarkResult, error) {
+	var partErr error
+	result := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N && partErr == nil; i++ {
+			_, partErr = part.Run(file)
+		}
+	})
+	return result, partErr
+}
+
+// The change from the baseline as a fraction, or 0 if there is no baseline value.
+func change(value, baseline int64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+	return float64(value-baseline) / float64(baseline)
+}
+
+// Benchmarks each part on the file, printing the time and allocations per run and
+// comparing them against the baseline. Returns an error if any part regressed.
+func Bench(file string, parts []Part, options BenchOptions) error {
+	baseline, err := readBaseline(options.baseline)
+	if err != nil {
+		return err
+	}
+
+	var regressions int
+	for _, part := range parts {
+		result, err := benchmarkPart(file, part)
+		if err != nil {
+			return fmt.Errorf("Part %v: %w", part.Name, err)
+		}
+		current := BenchResult{NsPerOp: result.NsPerOp(), AllocsPerOp: result.AllocsPerOp(), BytesPerOp: result.AllocedBytesPerOp()}
+		fmt.Printf("Part %v: %v runs, %v/op, %v B/op, %v allocs/op\n", part.Name, result.N, time.Duration(current.NsPerOp), current.BytesPerOp, current.AllocsPerOp)
+
+		if saved, ok := baseline[part.Name]; ok {
+			timeChange := change(current.NsPerOp, saved.NsPerOp)
+			allocChange := change(current.AllocsPerOp, saved.AllocsPerOp)
+			status := "ok"
+			if timeChange > options.threshold || allocChange > options.threshold {
+				status = "REGRESSION"
+				regressions++
+			}
+			fmt.Printf("  baseline %v/op (%+.1f%%), %v allocs/op (%+.1f%%): %v\n", time.Duration(saved.NsPerOp), 100*timeChange, saved.AllocsPerOp, 100*allocChange, status)
+		}
+		if options.saveBaseline {
+			baseline[part.Name] = current
+		}
+	}
+
+	if options.saveBaseline {
+		if err := writeBaseline(options.baseline, baseline); err != nil {
+			return err
+		}
+		fmt.Printf("Saved baseline to %v\n", options.baseline)
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%v part(s) regressed by more than %.0f%% against %v", regressions, 100*options.threshold, options.baseline)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+/***** Output *****/
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	TAPFormat  = "tap"
+)
+
+var formats = []string{TextFormat, JSONFormat, TAPFormat}
+
+// The answer a part gave and how long it took to find.
+type Result struct {
+	Day      int           `json:"day"`
+	Part     string        `json:"part"`
+	Answer   any           `json:"answer"`
+	Duration time.Duration `json:"durationNs"`
+}
+
+// Writes the results of parts as they finish, in one of the output formats.
+type reporter struct {
+	w      io.Writer
+	format string
+	// The number of results and failures written so far
+	count, failures int
+}
+
+func newReporter(w io.Writer, format string) (*reporter, error) {
+	for _, f := range formats {
+		if f == format {
+			return &reporter{w: w, format: format}, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown output format %q, expected one of %v", format, strings.Join(formats, ", "))
+}
+
+// Writes anything that comes before the results, given how many parts will run.
+func (r *reporter) start(numParts int) {
+	if r.format == TAPFormat {
+		fmt.Fprintln(r.w, "TAP version 13")
+		fmt.Fprintf(r.w, "1..%v\n", numParts)
+	}
+}
+
+func (r *reporter) result(result Result) error {
+	r.count++
+	switch r.format {
+	case JSONFormat:
+		return json.NewEncoder(r.w).Encode(result)
+	case TAPFormat:
+		_, err := fmt.Fprintf(r.w, "ok %v - day %v part %v: %v # time=%v\n", r.count, result.Day, result.Part, result.Answer, result.Duration)
+		return err
+	default:
+		_, err := fmt.Fprintf(r.w, "Day %v part %v: %v\n", result.Day, result.Part, result.Answer)
+		return err
+	}
+}
+
+// Records a part that failed. TAP reports it and carries on, other formats stop with the error.
+func (r *reporter) failure(day int, part string, err error) error {
+	r.count++
+	r.failures++
+	if r.format != TAPFormat {
+		return fmt.Errorf("Part %v: %w", part, err)
+	}
+	_, writeErr := fmt.Fprintf(r.w, "not ok %v - day %v part %v\n  ---\n  message: %q\n  ...\n", r.count, day, part, err.Error())
+	return writeErr
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+/***** Types *****/
+
+// A puzzle solution, run with -<name>. Parts are benchmarked by default with -bench, unless Slow.
+// Run returns the answer, which is printed in the format chosen with -format.
+type Part struct {
+	Name string
+	Run  func(file string) (any, error)
+	// Whether the part takes too long to benchmark unless it is selected
+	Slow bool
+}
+
+// Any other mode of a day, like a report, run with -<name>.
+type Tool struct {
+	Name  string
+	Usage string
+	Run   func(file string) error
+}
+
+type Day struct {
+	Number int
+	Parts  []Part
+	Tools  []Tool
+	// Called after the flags are parsed, to prepare anything the parts and tools need
+	Setup func() error
+}
+
+/***** Methods *****/
+
+// Parses the command line and runs the selected parts and tools of the day, in order.
+// Any flags specific to the day must be defined before calling Main.
+func (d Day) Main() {
+	inputFile := flag.String("file", "input.txt", "the input file to execute")
+	benchFlag := flag.Bool("bench", false, "whether to benchmark the selected parts, or every part that isn't slow if none are selected")
+	baselineFlag := flag.String("baseline", "bench_baseline.json", "the file of saved benchmark results to compare against")
+	saveBaselineFlag := flag.Bool("save-baseline", false, "whether to save the benchmark results as the new baseline")
+	thresholdFlag := flag.Float64("threshold", 0.2, "the fraction slower than the baseline that counts as a regression")
+	formatFlag := flag.String("format", TextFormat, fmt.Sprintf("the format to print answers in, one of %v", strings.Join(formats, ", ")))
+
+	partFlags := make([]*bool, len(d.Parts))
+	for i, part := range d.Parts {
+		partFlags[i] = flag.Bool(part.Name, false, fmt.Sprintf("whether to execute puzzle %v", part.Name))
+	}
+	toolFlags := make([]*bool, len(d.Tools))
+	for i, tool := range d.Tools {
+		toolFlags[i] = flag.Bool(tool.Name, false, tool.Usage)
+	}
+	flag.Parse()
+	out, err := newReporter(os.Stdout, *formatFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if d.Setup != nil {
+		if err := d.Setup(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var parts []Part
+	for i, part := range d.Parts {
+		if *partFlags[i] {
+			parts = append(parts, part)
+		}
+	}
+	var tools []Tool
+	for i, tool := range d.Tools {
+		if *toolFlags[i] {
+			tools = append(tools, tool)
+		}
+	}
+
+	if *benchFlag {
+		if len(parts) == 0 {
+			for _, part := range d.Parts {
+				if !part.Slow {
+					parts = append(parts, part)
+				}
+			}
+		}
+		options := BenchOptions{baseline: *baselineFlag, saveBaseline: *saveBaselineFlag, threshold: *thresholdFlag}
+		if err := Bench(*inputFile, parts, options); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(parts) == 0 && len(tools) == 0 {
+		fmt.Println("Nothing to do, specify a puzzle to solve")
+		return
+	}
+
+	out.start(len(parts))
+	for _, part := range parts {
+		start := time.Now()
+		answer, err := part.Run(*inputFile)
+		if err != nil {
+			err = out.failure(d.Number, part.Name, err)
+		} else {
+			err = out.result(Result{Day: d.Number, Part: part.Name, Answer: answer, Duration: time.Since(start)})
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, tool := range tools {
+		if err := tool.Run(*inputFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if out.failures > 0 {
+		os.Exit(1)
+	}
+}